@@ -0,0 +1,146 @@
+package bulk
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh/guardrails"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// Options holds the flags to edit the labels of many items (search results or a list of numbers) instead of a single item.
+type Options struct {
+	kind    labels.ItemKind
+	filter  func(cmd *cobra.Command) string
+	numbers []string
+	search  string
+	yes     bool
+	dryrun  bool
+}
+
+// NewOptions returns the options for items of the kind. filter, if not nil, returns the qualifiers added to the search query,
+// e.g. is:pr when the issue commands are called as pr.
+func NewOptions(kind labels.ItemKind, filter func(cmd *cobra.Command) string) *Options {
+	return &Options{kind: kind, filter: filter}
+}
+
+// items returns the plural noun of the items for messages.
+func (o *Options) items() string {
+	return string(o.kind) + "s"
+}
+
+// AddFlags adds the --number, --search, --yes and --dryrun flags to the command.
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.BoolVarP(&o.dryrun, "dryrun", "n", false, fmt.Sprintf("Dry run: show the target %s without changing labels (with --search or --number)", o.items()))
+	f.StringSliceVar(&o.numbers, "number", nil, fmt.Sprintf("Apply to the %s with the numbers or URLs instead of <number> (\"-\" reads them from stdin)", o.items()))
+	f.StringVar(&o.search, "search", "", fmt.Sprintf("Apply to every %s matching the search query instead of <number>", o.kind))
+	f.BoolVarP(&o.yes, "yes", "y", false, "Do not ask for confirmation (with --search)")
+	cmd.MarkFlagsMutuallyExclusive("number", "search")
+}
+
+// Enabled returns whether the command targets many items.
+func (o *Options) Enabled() bool {
+	return o.search != "" || len(o.numbers) > 0
+}
+
+// Args validates the positional arguments: <number> is omitted with --search and --number.
+func (o *Options) Args(cmd *cobra.Command, args []string) error {
+	if o.Enabled() {
+		return cobra.MinimumNArgs(1)(cmd, args)
+	}
+	return cobra.MinimumNArgs(2)(cmd, args)
+}
+
+// Run applies the label operation to every target item and renders the per-target results.
+func (o *Options) Run(cmd *cobra.Command, repo string, operation labels.LabelOperation, labelNames []string, exporter cmdutil.Exporter, colorFlag string) error {
+	var targets []labels.ItemTarget
+	var client *gh.GitHubClient
+	var err error
+	if o.search != "" {
+		targets, client, err = o.searchTargets(cmd, repo)
+	} else {
+		targets, client, err = o.numberTargets(cmd, repo)
+	}
+	if err != nil || len(targets) == 0 {
+		return err
+	}
+
+	dryrun := o.dryrun || guardrails.IsReadonly()
+	if o.search != "" && !dryrun && !o.yes {
+		ok, err := labels.Confirm(fmt.Sprintf("%s labels %v on %d %s?", operation, labelNames, len(targets), o.items()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("operation cancelled")
+		}
+	}
+	editor := &labels.ItemLabelEditor{
+		Kind:      o.kind,
+		Operation: operation,
+		Labels:    labelNames,
+		DryRun:    dryrun,
+	}
+	results := editor.Run(cmd.Context(), client, targets)
+
+	renderer := labels.NewRenderer(exporter)
+	renderer.SetColor(colorFlag)
+	if err := renderer.RenderItemResults(results); err != nil {
+		return err
+	}
+	if failed := labels.CountFailed(results); failed > 0 {
+		return fmt.Errorf("failed to %s labels on %d of %d %s", operation, failed, len(results), o.items())
+	}
+	return nil
+}
+
+// searchTargets returns the items matching the search query.
+func (o *Options) searchTargets(cmd *cobra.Command, repo string) ([]labels.ItemTarget, *gh.GitHubClient, error) {
+	repository, err := parser.Repository(parser.RepositoryInput(repo))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve repository: %w", err)
+	}
+	client, err := gh.NewGitHubClientWithRepo(repository)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	query, err := labels.BuildSearchQuery([]string{o.search}, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.filter != nil {
+		if filter := o.filter(cmd); filter != "" {
+			query += " " + filter
+		}
+	}
+	targets, err := labels.SearchItemTargets(cmd.Context(), client, repository, o.kind, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search %s: %w", o.items(), err)
+	}
+	if len(targets) == 0 {
+		logger.Info(fmt.Sprintf("No %s matched the search query", o.items()), "query", query)
+	}
+	return targets, client, nil
+}
+
+// numberTargets returns the items given by --number.
+func (o *Options) numberTargets(cmd *cobra.Command, repo string) ([]labels.ItemTarget, *gh.GitHubClient, error) {
+	targets, err := labels.ParseItemTargets(o.kind, o.numbers, repo, cmd.InOrStdin())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(targets) == 0 {
+		logger.Info(fmt.Sprintf("No %s were given", o.items()))
+		return nil, nil, nil
+	}
+	client, err := gh.NewGitHubClientWithRepo(targets[0].Repository)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	return targets, client, nil
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/cmd/bulk"
+	"github.com/srz-zumix/gh-label-kit/labels"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 	"github.com/srz-zumix/go-gh-extension/pkg/parser"
 	"github.com/srz-zumix/go-gh-extension/pkg/render"
@@ -18,12 +20,16 @@ func NewRemoveCmd() *cobra.Command {
 	opts := &RemoveOptions{}
 	var colorFlag string
 	var repo string
+	bulkOpts := bulk.NewOptions(labels.ItemKindDiscussion, nil)
 	cmd := &cobra.Command{
-		Use:   "remove <number> <label>...",
+		Use:   "remove {<number> | --number <number>,... | --search <query>} <label>...",
 		Short: "Remove label(s) from a discussion",
-		Long:  `Remove one or more labels from a discussion in the repository.`,
-		Args:  cobra.MinimumNArgs(2),
+		Long:  `Remove one or more labels from a discussion in the repository. With --number, the labels are changed on every given discussion; with --search, on every discussion matching the query after confirmation.`,
+		Args:  bulkOpts.Args,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if bulkOpts.Enabled() {
+				return bulkOpts.Run(cmd, repo, labels.LabelOperationRemove, args, opts.Exporter, colorFlag)
+			}
 			target := args[0]
 			labelsToRemove := args[1:]
 			repository, err := parser.Repository(parser.RepositoryInput(repo), parser.RepositoryFromURL(target))
@@ -35,18 +41,19 @@ func NewRemoveCmd() *cobra.Command {
 				return fmt.Errorf("failed to create GitHub client: %w", err)
 			}
 			ctx := cmd.Context()
-			labels, err := gh.RemoveDiscussionLabels(ctx, client, repository, target, labelsToRemove)
+			result, err := gh.RemoveDiscussionLabels(ctx, client, repository, target, labelsToRemove)
 			if err != nil {
 				return fmt.Errorf("failed to remove labels from discussion %s: %w", target, err)
 			}
 			renderer := render.NewRenderer(opts.Exporter)
 			renderer.SetColor(colorFlag)
-			return renderer.RenderLabels(labels, nil)
+			return renderer.RenderLabels(result, nil)
 		},
 	}
 	f := cmd.Flags()
 	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
 	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	bulkOpts.AddFlags(cmd)
 	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
 	return cmd
 }
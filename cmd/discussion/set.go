@@ -5,6 +5,8 @@ import (
 
 	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/cmd/bulk"
+	"github.com/srz-zumix/gh-label-kit/labels"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 	"github.com/srz-zumix/go-gh-extension/pkg/parser"
 	"github.com/srz-zumix/go-gh-extension/pkg/render"
@@ -18,14 +20,18 @@ func NewSetCmd() *cobra.Command {
 	opts := &SetOptions{}
 	var colorFlag string
 	var repo string
+	bulkOpts := bulk.NewOptions(labels.ItemKindDiscussion, nil)
 	cmd := &cobra.Command{
-		Use:   "set <number> <label>...",
+		Use:   "set {<number> | --number <number>,... | --search <query>} <label>...",
 		Short: "Set labels for a discussion (replace all)",
-		Long:  `Set (replace) all labels for a discussion in the repository.`,
-		Args:  cobra.MinimumNArgs(2),
+		Long:  `Set (replace) all labels for a discussion in the repository. With --number, the labels are changed on every given discussion; with --search, on every discussion matching the query after confirmation.`,
+		Args:  bulkOpts.Args,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if bulkOpts.Enabled() {
+				return bulkOpts.Run(cmd, repo, labels.LabelOperationSet, args, opts.Exporter, colorFlag)
+			}
 			target := args[0]
-			labels := args[1:]
+			labelNames := args[1:]
 			repository, err := parser.Repository(parser.RepositoryInput(repo), parser.RepositoryFromURL(target))
 			if err != nil {
 				return fmt.Errorf("failed to resolve repository: %w", err)
@@ -35,7 +41,7 @@ func NewSetCmd() *cobra.Command {
 				return fmt.Errorf("failed to create GitHub client: %w", err)
 			}
 			ctx := cmd.Context()
-			result, err := gh.SetDiscussionLabels(ctx, client, repository, target, labels)
+			result, err := gh.SetDiscussionLabels(ctx, client, repository, target, labelNames)
 			if err != nil {
 				return fmt.Errorf("failed to set labels for discussion %s: %w", target, err)
 			}
@@ -47,6 +53,7 @@ func NewSetCmd() *cobra.Command {
 	f := cmd.Flags()
 	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
 	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	bulkOpts.AddFlags(cmd)
 	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
 	return cmd
 }
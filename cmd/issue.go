@@ -18,6 +18,7 @@ func NewIssueCmd() *cobra.Command {
 	cmd.AddCommand(issue.NewAddCmd())
 	cmd.AddCommand(issue.NewRemoveCmd())
 	cmd.AddCommand(issue.NewClearCmd())
+	cmd.AddCommand(issue.NewHistoryCmd())
 	cmd.AddCommand(issue.NewSetCmd())
 	cmd.AddCommand(issue.NewSearchCmd())
 	return cmd
@@ -30,3 +30,11 @@ func IsCalledViaAlias(cmd *cobra.Command, aliasName string) bool {
 func IsPRCommand(cmd *cobra.Command) bool {
 	return IsCalledViaAlias(cmd, "pr")
 }
+
+// prFilter returns the search qualifier that restricts a search to pull requests if the command was called via the "pr" alias.
+func prFilter(cmd *cobra.Command) string {
+	if IsPRCommand(cmd) {
+		return "is:pr"
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package issue
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
+)
+
+type HistoryOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+func NewHistoryCmd() *cobra.Command {
+	opts := &HistoryOptions{}
+	var automation []string
+	var colorFlag string
+	var repo string
+	cmd := &cobra.Command{
+		Use:   "history <number>",
+		Short: "Show label history for a issue",
+		Long:  `Show when each label was added to or removed from a issue or pull request, and by whom, from the issue timeline. Actors that are bot accounts or match an --automation pattern are marked as automation.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Accept pull request URLs as well, since the command is also available as pr history
+			target, err := labels.ParseItemTarget(labels.ItemKindIssue, args[0], repo)
+			if err != nil {
+				return fmt.Errorf("failed to parse issue %s: %w", args[0], err)
+			}
+			matcher, err := labels.NewAutomationMatcher(automation)
+			if err != nil {
+				return err
+			}
+			client, err := gh.NewGitHubClientWithRepo(target.Repository)
+			if err != nil {
+				return fmt.Errorf("failed to create GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			events, err := labels.ListLabelHistory(ctx, client, target.Repository, target.Number, matcher)
+			if err != nil {
+				return fmt.Errorf("failed to get label history of %s: %w", target, err)
+			}
+			renderer := labels.NewRenderer(opts.Exporter)
+			renderer.SetColor(colorFlag)
+			return renderer.RenderLabelEvents(events)
+		},
+	}
+	f := cmd.Flags()
+	f.StringSliceVar(&automation, "automation", nil, "Login pattern (regexp) of the automation account (bot accounts are always automation)")
+	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
+	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+	return cmd
+}
@@ -2,10 +2,10 @@ package issue
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 	"github.com/srz-zumix/go-gh-extension/pkg/parser"
 	"github.com/srz-zumix/go-gh-extension/pkg/render"
@@ -20,25 +20,16 @@ func NewSearchCmd() *cobra.Command {
 	var colorFlag string
 	var repo string
 	var owner string
-	var labels []string
+	var labelNames []string
 	cmd := &cobra.Command{
 		Use:   "search [query...]",
 		Short: "Search issues by query",
 		Long:  `Search issues in the repository using a search query. The query can include label filters and other search criteria.`,
 		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			query := strings.Join(args, " ")
-			if len(labels) > 0 {
-				for _, label := range labels {
-					query += fmt.Sprintf(" label:\"%s\"", label)
-				}
-			}
-
-			if owner != "" {
-				query += " org:" + owner
-			}
-			if repo != "" {
-				query += " repo:" + repo
+			query, err := labels.BuildSearchQuery(args, labelNames, owner)
+			if err != nil {
+				return err
 			}
 
 			// Check if command was called via alias
@@ -71,7 +62,7 @@ func NewSearchCmd() *cobra.Command {
 	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
 	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
 	f.StringVar(&owner, "owner", "", "Specify the organization name")
-	f.StringSliceVarP(&labels, "label", "l", []string{}, "Filter issues by labels")
+	f.StringSliceVarP(&labelNames, "label", "l", []string{}, "Filter issues by labels")
 	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
 	return cmd
 }
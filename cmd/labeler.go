@@ -2,12 +2,14 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/spf13/cobra"
+	labelercmd "github.com/srz-zumix/gh-label-kit/cmd/labeler"
 	"github.com/srz-zumix/gh-label-kit/labeler"
+	"github.com/srz-zumix/gh-label-kit/labels"
 	"github.com/srz-zumix/go-gh-extension/pkg/actions"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 	"github.com/srz-zumix/go-gh-extension/pkg/logger"
@@ -15,8 +17,6 @@ import (
 	"github.com/srz-zumix/go-gh-extension/pkg/render"
 )
 
-var defaultConfigPath = ".github/labeler.yml"
-
 type LabelerOptions struct {
 	Exporter cmdutil.Exporter
 }
@@ -35,6 +35,15 @@ func NewLabelerCmd() *cobra.Command {
 	var skipLocalConfig bool
 	var strictConfig bool
 	var noHidden bool
+	var ignoreGenerated bool
+	var explain bool
+	var noCreate bool
+	var respectManual bool
+	var automation []string
+	var allowCommands bool
+	var commandTimeout time.Duration
+	var commandEnv []string
+	var labelCommands []string
 	cmd := &cobra.Command{
 		Use:   "labeler <pr-number...>",
 		Short: "Automatically label PRs based on changed files and branch name using config file",
@@ -54,54 +63,30 @@ func NewLabelerCmd() *cobra.Command {
 				return fmt.Errorf("error creating GitHub client: %w", err)
 			}
 
-			var cfg labeler.LabelerConfig
-			if !skipLocalConfig {
-				if labeler.ConfigFileExists(configPath) {
-					cfg, err = labeler.LoadConfig(configPath, strictConfig)
-					if err != nil {
-						// If local config exists but failed to load, return error immediately
-						// Don't fallback to remote config in this case
-						return fmt.Errorf("failed to load local config: %w", err)
-					}
-				}
+			ctx := cmd.Context()
+			// If a single PR is specified, its head branch is used as the ref when no ref is given
+			headRefPR := ""
+			if len(args) == 1 {
+				headRefPR = args[0]
+			}
+			cfg, err := labeler.LoadConfigFromPath(ctx, client, repository, configPath, ref, headRefPR, skipLocalConfig, strictConfig)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
 			}
 
-			ctx := cmd.Context()
-			// Load config from repository if local config is skipped or doesn't exist
-			if cfg == nil {
-				if ref == "" {
-					ref = os.Getenv("GITHUB_SHA")
-				}
-				contentPaths, err := parser.ParseContentPath(configPath)
+			if ignoreGenerated {
+				generated, err := labeler.LoadGeneratedGlobs(".")
 				if err != nil {
-					return fmt.Errorf("failed to parse config path: %w", err)
-				}
-				if contentPaths.Ref == nil {
-					if contentPaths.Repo == nil {
-						contentPaths.Ref = &ref
-						// If ref is still empty and only one PR is specified, use PR's head branch
-						if ref == "" && len(args) == 1 {
-							pr, err := gh.GetPullRequest(ctx, client, repository, args[0])
-							if err != nil {
-								return fmt.Errorf("failed to get PR %s to resolve ref: %w", args[0], err)
-							}
-							if pr != nil && pr.GetHead() != nil && pr.GetHead().GetRef() != "" {
-								ref = pr.GetHead().GetRef()
-								logger.Info("Using PR head branch as ref", "pr", args[0], "ref", ref)
-							}
-						}
-					}
-				}
-				if contentPaths.Repo == nil {
-					contentPaths.Repo = &repository
-				}
-				if contentPaths.Path == nil {
-					contentPaths.Path = &defaultConfigPath
+					return fmt.Errorf("failed to load generated files: %w", err)
 				}
+				cfg.AddIgnoreFiles(generated)
+			}
 
-				cfg, err = labeler.LoadConfigFromRepo(ctx, client, *contentPaths.Repo, *contentPaths.Path, contentPaths.Ref, strictConfig)
+			var automationMatcher *labels.AutomationMatcher
+			if respectManual || cfg.HasRespectManual() {
+				automationMatcher, err = labels.NewAutomationMatcher(automation)
 				if err != nil {
-					return fmt.Errorf("failed to load config from repository: %w", err)
+					return err
 				}
 			}
 
@@ -117,7 +102,39 @@ func NewLabelerCmd() *cobra.Command {
 				}
 
 				matcher := labeler.NewMatcher(ctx, client)
-				result := matcher.CheckMatchConfigs(cfg, changedFiles, pr)
+				matcher.SetCommandOptions(labeler.CommandOptions{Allow: allowCommands, Timeout: commandTimeout, Env: commandEnv})
+				var result labeler.MatchResult
+				if explain {
+					var traces []*labels.MatchTrace
+					result, traces = matcher.ExplainMatchConfigs(cfg, changedFiles, pr)
+					renderer := labels.NewRenderer(nil)
+					if opts.Exporter != nil {
+						// Keep the standard output valid for --format, --jq and --template
+						renderer.IO.Out = renderer.IO.ErrOut
+					}
+					renderer.SetColor(colorFlag)
+					renderer.WriteLine(fmt.Sprintf("Label conditions for PR #%s", prNumber))
+					if err := renderer.RenderMatchTraces(traces); err != nil {
+						return fmt.Errorf("failed to render label conditions for PR %s: %w", prNumber, err)
+					}
+				} else {
+					result = matcher.CheckMatchConfigs(cfg, changedFiles, pr)
+				}
+				if len(labelCommands) > 0 {
+					commandLabels, err := matcher.RunLabelCommands(labelCommands, changedFiles, pr)
+					if err != nil {
+						return fmt.Errorf("failed to run label commands for PR %s: %w", prNumber, err)
+					}
+					logger.Debug("Labels from label commands", "pr", prNumber, "labels", commandLabels)
+					result = result.AddMatched(commandLabels)
+				}
+				if automationMatcher != nil {
+					overrides, err := labeler.GetManualOverrides(ctx, client, repository, pr, automationMatcher)
+					if err != nil {
+						return fmt.Errorf("failed to get manual label changes for PR %s: %w", prNumber, err)
+					}
+					result = result.RespectManual(cfg, overrides, respectManual)
+				}
 				allLabels := result.GetLabels(syncLabels)
 				labeledCodeOwners := labeler.NewLabeledCodeOwners(ctx, client, repository, pr, cfg, reviewRequest)
 				reviewRequestLabels := labeler.GetReviewRequestTargetLabels(pr, result, reviewRequest, syncLabels)
@@ -125,6 +142,17 @@ func NewLabelerCmd() *cobra.Command {
 				if dryrun {
 					if result.HasDiff(syncLabels) {
 						logger.Info("Would set labels for PR", "pr", prNumber, "current", result.Current, "new", allLabels)
+						undefined, err := labeler.FindUndefinedLabels(ctx, client, repository, allLabels)
+						if err != nil {
+							return fmt.Errorf("failed to check labels for PR %s: %w", prNumber, err)
+						}
+						if len(undefined) > 0 {
+							if noCreate {
+								logger.Info("Would skip labels not defined in repository", "pr", prNumber, "labels", undefined)
+							} else {
+								logger.Info("Would create labels", "pr", prNumber, "labels", undefined)
+							}
+						}
 					} else {
 						logger.Info("No label changes for PR", "pr", prNumber, "labels", allLabels)
 					}
@@ -137,7 +165,7 @@ func NewLabelerCmd() *cobra.Command {
 					labels := pr.Labels
 					if result.HasDiff(syncLabels) {
 						renderer.WriteLine(fmt.Sprintf("Labels set for PR #%s", prNumber))
-						labels, err = labeler.SetLabels(ctx, client, repository, pr, allLabels, cfg)
+						labels, err = labeler.SetLabels(ctx, client, repository, pr, allLabels, cfg, noCreate)
 						if err != nil {
 							return fmt.Errorf("failed to set labels for PR %s: %w", prNumber, err)
 						}
@@ -185,17 +213,32 @@ func NewLabelerCmd() *cobra.Command {
 	f := cmd.Flags()
 	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
 	f.StringVarP(&repo, "repo", "R", "", "Target repository in the format 'owner/repo'")
-	f.StringVar(&configPath, "config", defaultConfigPath, "Path to labeler config YAML file, path in repo, or GitHub URL, or actions format (owner/repo[/path]@ref)")
+	f.StringVar(&configPath, "config", labeler.DefaultConfigPath, "Path to labeler config YAML file, path in repo, or GitHub URL, or actions format (owner/repo[/path]@ref)")
 	f.BoolVar(&nameOnly, "name-only", false, "Output only team names")
 	f.BoolVar(&syncLabels, "sync", false, "Remove labels not matching any condition")
 	f.BoolVarP(&dryrun, "dryrun", "n", false, "Dry run: do not actually set labels")
+	f.BoolVar(&explain, "explain", false, "Show how the conditions of each label were evaluated")
+	f.BoolVar(&respectManual, "respect-manual", false, "Do not re-add labels a human removed or remove labels a human added")
 	f.StringVar(&ref, "ref", "", "Git reference (branch, tag, or commit SHA) to load config from repository")
 	f.BoolVar(&skipLocalConfig, "skip-local-config", false, "Skip loading config from local file and load from repository instead")
 	f.BoolVar(&strictConfig, "strict", false, "Treat unknown fields in config as errors instead of warnings")
 	f.BoolVar(&noHidden, "no-hidden", false, "Exclude hidden files (files starting with .) from glob matching")
+	f.BoolVar(&ignoreGenerated, "ignore-generated", false, "Ignore changed files marked as linguist-generated in the local .gitattributes")
+	f.BoolVar(&allowCommands, "allow-commands", false, "Run the command conditions of the config")
+	f.DurationVar(&commandTimeout, "command-timeout", labeler.DefaultCommandTimeout, "Time limit of each command condition and label command")
+	f.StringSliceVar(&commandEnv, "command-env", nil, "Name of an environment variable to pass to commands in addition to the default ones")
+	f.StringArrayVar(&labelCommands, "label-command", nil, "Command whose output lines are labels to add to the PR (can be repeated)")
+	f.StringSliceVar(&automation, "automation", nil, "Login pattern (regexp) of the automation account for --respect-manual (bot accounts are always automation)")
+	f.BoolVar(&noCreate, "no-create", false, "Do not create labels that are not defined in the repository")
 	cmdutil.StringEnumFlag(cmd, &reviewRequest, "review-request", "", labeler.ReviewRequestModeAddTo, labeler.ReviewersRequestModes, "Control review request behavior based on CODEOWNERS when labels are applied")
 	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
 
+	cmd.AddCommand(labelercmd.NewApplyMetadataCmd())
+	cmd.AddCommand(labelercmd.NewBacktestCmd())
+	cmd.AddCommand(labelercmd.NewCoverageCmd())
+	cmd.AddCommand(labelercmd.NewInitCmd())
+	cmd.AddCommand(labelercmd.NewSuggestCmd())
+
 	return cmd
 }
 
@@ -0,0 +1,83 @@
+package labeler
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labeler"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
+)
+
+type ApplyMetadataOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+// NewApplyMetadataCmd implements a command to push label colors and descriptions in the labeler config to the repository.
+func NewApplyMetadataCmd() *cobra.Command {
+	opts := &ApplyMetadataOptions{}
+	var colorFlag string
+	var repo string
+	var configPath string
+	var ref string
+	var skipLocalConfig bool
+	var strictConfig bool
+	var dryrun bool
+	var prune bool
+	cmd := &cobra.Command{
+		Use:   "apply-metadata",
+		Short: "Apply label colors and descriptions in the labeler config to the repository",
+		Long:  `Apply the color and description of every label in the labeler config to the repository labels, creating labels that do not exist. Use --dryrun to show the changes without applying them, and --prune to delete repository labels that are not in the config.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repository, err := parser.Repository(parser.RepositoryInput(repo))
+			if err != nil {
+				return fmt.Errorf("error parsing repository: %w", err)
+			}
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("error creating GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			cfg, err := labeler.LoadConfigFromPath(ctx, client, repository, configPath, ref, "", skipLocalConfig, strictConfig)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			plan, err := labels.ComputePlan(ctx, client, repository, cfg.Definitions(), labels.DiffOptions{Update: true, Prune: prune})
+			if err != nil {
+				return fmt.Errorf("failed to compute label changes for %s: %w", parser.GetRepositoryFullName(repository), err)
+			}
+
+			renderer := labels.NewRenderer(opts.Exporter)
+			renderer.SetColor(colorFlag)
+			if err := renderer.RenderPlans([]*labels.Plan{plan}); err != nil {
+				return err
+			}
+			if dryrun {
+				return nil
+			}
+			if err := plan.Apply(ctx, client); err != nil {
+				return fmt.Errorf("failed to apply label changes to %s: %w", parser.GetRepositoryFullName(repository), err)
+			}
+			logger.Info("Successfully applied label metadata", "repository", parser.GetRepositoryFullName(repository), "changes", len(plan.Changes))
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
+	f.StringVarP(&repo, "repo", "R", "", "Target repository in the format 'owner/repo'")
+	f.StringVar(&configPath, "config", labeler.DefaultConfigPath, "Path to labeler config YAML file, path in repo, or GitHub URL, or actions format (owner/repo[/path]@ref)")
+	f.StringVar(&ref, "ref", "", "Git reference (branch, tag, or commit SHA) to load config from repository")
+	f.BoolVar(&skipLocalConfig, "skip-local-config", false, "Skip loading config from local file and load from repository instead")
+	f.BoolVar(&strictConfig, "strict", false, "Treat unknown fields in config as errors instead of warnings")
+	f.BoolVarP(&dryrun, "dryrun", "n", false, "Dry run: show the changes without applying them")
+	f.BoolVar(&prune, "prune", false, "Delete repository labels that are not in the config")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+
+	return cmd
+}
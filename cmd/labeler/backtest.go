@@ -0,0 +1,120 @@
+package labeler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labeler"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
+)
+
+type BacktestOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+// NewBacktestCmd implements a command to replay a labeler config over recently closed PRs.
+func NewBacktestCmd() *cobra.Command {
+	opts := &BacktestOptions{}
+	var colorFlag string
+	var repo string
+	var configPath string
+	var baselinePath string
+	var ref string
+	var skipLocalConfig bool
+	var strictConfig bool
+	var last int
+	var cacheDir string
+	var noCache bool
+	var changedOnly bool
+	var allowCommands bool
+	var commandTimeout time.Duration
+	var commandEnv []string
+	cmd := &cobra.Command{
+		Use:   "backtest",
+		Short: "Replay a labeler config over recently closed PRs",
+		Long:  `Replay the labeler config over the most recently updated merged or closed PRs and report, per label, how many PRs would gain or lose it compared with their actual labels, or with a baseline config if --baseline is specified. The changed files of each PR are cached locally so that repeated runs are fast.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if last <= 0 {
+				return fmt.Errorf("--last must be greater than 0")
+			}
+			repository, err := parser.Repository(parser.RepositoryInput(repo))
+			if err != nil {
+				return fmt.Errorf("error parsing repository: %w", err)
+			}
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("error creating GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			cfg, err := labeler.LoadConfigFromPath(ctx, client, repository, configPath, ref, "", skipLocalConfig, strictConfig)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			var baseline labeler.LabelerConfig
+			if baselinePath != "" {
+				baseline, err = labeler.LoadConfigFromPath(ctx, client, repository, baselinePath, ref, "", skipLocalConfig, strictConfig)
+				if err != nil {
+					return fmt.Errorf("failed to load baseline config: %w", err)
+				}
+			}
+
+			var cache *labeler.FileCache
+			if !noCache {
+				if cacheDir == "" {
+					cacheDir, err = labeler.DefaultFileCacheDir()
+					if err != nil {
+						return fmt.Errorf("failed to resolve cache directory: %w", err)
+					}
+				}
+				cache = &labeler.FileCache{Dir: cacheDir}
+			}
+
+			prs, err := labeler.ListRecentClosedPullRequests(ctx, client, repository, last)
+			if err != nil {
+				return fmt.Errorf("failed to list PRs for %s: %w", parser.GetRepositoryFullName(repository), err)
+			}
+			impacts, err := labeler.Backtest(ctx, client, repository, cfg, baseline, prs, cache, labeler.CommandOptions{Allow: allowCommands, Timeout: commandTimeout, Env: commandEnv})
+			if err != nil {
+				return fmt.Errorf("failed to backtest config: %w", err)
+			}
+			if changedOnly {
+				var filtered []*labels.LabelImpact
+				for _, i := range impacts {
+					if i.HasChange() {
+						filtered = append(filtered, i)
+					}
+				}
+				impacts = filtered
+			}
+
+			renderer := labels.NewRenderer(opts.Exporter)
+			renderer.SetColor(colorFlag)
+			return renderer.RenderLabelImpacts(impacts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVar(&allowCommands, "allow-commands", false, "Run the command conditions of the config")
+	f.StringVar(&baselinePath, "baseline", "", "Path to the labeler config to compare with instead of the actual labels of the PRs")
+	f.StringVar(&cacheDir, "cache-dir", "", "Directory to cache the changed files of PRs (default: the user cache directory)")
+	f.BoolVar(&changedOnly, "changed-only", false, "Show only labels that any PR would gain or lose")
+	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
+	f.StringSliceVar(&commandEnv, "command-env", nil, "Name of an environment variable to pass to commands in addition to the default ones")
+	f.DurationVar(&commandTimeout, "command-timeout", labeler.DefaultCommandTimeout, "Time limit of each command condition")
+	f.StringVar(&configPath, "config", labeler.DefaultConfigPath, "Path to labeler config YAML file, path in repo, or GitHub URL, or actions format (owner/repo[/path]@ref)")
+	f.IntVar(&last, "last", 100, "Number of most recently updated closed PRs to replay")
+	f.BoolVar(&noCache, "no-cache", false, "Do not cache the changed files of PRs")
+	f.StringVar(&ref, "ref", "", "Git reference (branch, tag, or commit SHA) to load config from repository")
+	f.StringVarP(&repo, "repo", "R", "", "Target repository in the format 'owner/repo'")
+	f.BoolVar(&skipLocalConfig, "skip-local-config", false, "Skip loading config from local file and load from repository instead")
+	f.BoolVar(&strictConfig, "strict", false, "Treat unknown fields in config as errors instead of warnings")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+
+	return cmd
+}
@@ -0,0 +1,86 @@
+package labeler
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labeler"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+type CoverageOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+// NewCoverageCmd implements a command to report how well the changed-files globs of a labeler config cover the repository files.
+func NewCoverageCmd() *cobra.Command {
+	opts := &CoverageOptions{}
+	var repo string
+	var configPath string
+	var ref string
+	var skipLocalConfig bool
+	var strictConfig bool
+	var noHidden bool
+	var minOverlap float64
+	cmd := &cobra.Command{
+		Use:   "coverage [<path>]",
+		Short: "Report files and globs not covered by a labeler config",
+		Long:  `Walk a local repository tree (default: the current directory), or the repository tree at --ref, and report the files that no label's changed-files globs match, the globs that match no file, and the labels whose globs match many of the same files. A file counts as matched by a label if any of the label's globs matches it, regardless of how the globs are combined in the rule.`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 && ref != "" {
+				return fmt.Errorf("<path> and --ref cannot be used together")
+			}
+			repository, err := parser.Repository(parser.RepositoryInput(repo))
+			if err != nil {
+				return fmt.Errorf("error parsing repository: %w", err)
+			}
+
+			// Set no-hidden option for glob matching
+			labeler.SetNoHidden(noHidden)
+
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("error creating GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			cfg, err := labeler.LoadConfigFromPath(ctx, client, repository, configPath, ref, "", skipLocalConfig, strictConfig)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			var files []string
+			if ref != "" {
+				files, err = labeler.ListRepositoryFiles(ctx, client, repository, ref)
+			} else {
+				root := "."
+				if len(args) > 0 {
+					root = args[0]
+				}
+				files, err = labeler.ListLocalFiles(root)
+			}
+			if err != nil {
+				return err
+			}
+
+			report := labeler.ComputeCoverage(cfg, files, minOverlap)
+			renderer := labels.NewRenderer(opts.Exporter)
+			return renderer.RenderCoverageReport(report)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&configPath, "config", labeler.DefaultConfigPath, "Path to labeler config YAML file, path in repo, or GitHub URL, or actions format (owner/repo[/path]@ref)")
+	f.Float64Var(&minOverlap, "min-overlap", labeler.DefaultMinOverlap, "Report labels sharing at least this ratio of the files of the label with fewer files")
+	f.BoolVar(&noHidden, "no-hidden", false, "Exclude hidden files (files starting with .) from glob matching")
+	f.StringVar(&ref, "ref", "", "Git reference (branch, tag, or commit SHA) to check the repository tree and load config from instead of a local tree")
+	f.StringVarP(&repo, "repo", "R", "", "Target repository in the format 'owner/repo'")
+	f.BoolVar(&skipLocalConfig, "skip-local-config", false, "Skip loading config from local file and load from repository instead")
+	f.BoolVar(&strictConfig, "strict", false, "Treat unknown fields in config as errors instead of warnings")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+
+	return cmd
+}
@@ -0,0 +1,110 @@
+package labeler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labeler"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// NewInitCmd implements a command to scaffold a labeler config from the repository structure and CODEOWNERS.
+func NewInitCmd() *cobra.Command {
+	var output string
+	var prefix string
+	var force bool
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "init [<path>]",
+		Short: "Scaffold a labeler config from the repository structure",
+		Long:  `Scan a local repository tree (default: the current directory) and its CODEOWNERS file, propose an area label for each top-level directory and Go module, with a color and the owners of the area as codeowners, and write the selected labels as a labeler config. Proposals are selected interactively unless --yes is specified.`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := "."
+			if len(args) > 0 {
+				root = args[0]
+			}
+			if output == "" {
+				// Write into the scanned repository, not the current directory
+				output = filepath.Join(root, labeler.DefaultConfigPath)
+			}
+			files, err := labeler.ListLocalFiles(root)
+			if err != nil {
+				return err
+			}
+			owners, err := labeler.LoadCodeowners(root)
+			if err != nil {
+				return fmt.Errorf("failed to load CODEOWNERS: %w", err)
+			}
+			areas := labeler.ScanAreas(files, prefix)
+			labeler.AssignCodeowners(areas, files, owners)
+			if len(areas) == 0 {
+				return fmt.Errorf("no directory to propose as a label in %s", root)
+			}
+
+			if !yes {
+				options := make([]string, len(areas))
+				for i, a := range areas {
+					options[i] = fmt.Sprintf("%s (%s, %d files)", a.Label, a.Glob(), a.Files)
+					if len(a.Codeowners) > 0 {
+						options[i] += " " + strings.Join(a.Codeowners, " ")
+					}
+				}
+				selected, err := labels.SelectMany("Select the labels to add to the labeler config", options)
+				if err != nil {
+					return err
+				}
+				chosen := make([]labeler.Area, 0, len(selected))
+				for _, i := range selected {
+					chosen = append(chosen, areas[i])
+				}
+				areas = chosen
+				if len(areas) == 0 {
+					logger.Info("No label selected, nothing to write")
+					return nil
+				}
+			}
+
+			content := labeler.FormatConfig(areas)
+			if output == "-" {
+				_, err := fmt.Fprint(cmd.OutOrStdout(), content)
+				return err
+			}
+			if _, err := os.Stat(output); err == nil && !force {
+				if yes {
+					return fmt.Errorf("%s already exists: use --force to overwrite it", output)
+				}
+				ok, err := labels.Confirm(fmt.Sprintf("Overwrite %s?", output))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return nil
+				}
+			} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to check %s: %w", output, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+				return fmt.Errorf("failed to create directory for %s: %w", output, err)
+			}
+			if err := os.WriteFile(output, []byte(content), 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", output, err)
+			}
+			logger.Info("Wrote labeler config", "path", output, "labels", len(areas))
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVar(&force, "force", false, "Overwrite the output file if it exists")
+	f.StringVarP(&output, "output", "o", "", "Path to write the labeler config to, or '-' for standard output (default: .github/labeler.yml in <path>)")
+	f.StringVar(&prefix, "prefix", labeler.DefaultAreaPrefix, "Prefix of the proposed label names")
+	f.BoolVarP(&yes, "yes", "y", false, "Accept all proposals without prompting")
+
+	return cmd
+}
@@ -0,0 +1,101 @@
+package labeler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labeler"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
+)
+
+type SuggestOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+// NewSuggestCmd implements a command to propose changed-files rules learned from the labels of merged PRs.
+func NewSuggestCmd() *cobra.Command {
+	opts := &SuggestOptions{}
+	thresholds := labeler.DefaultSuggestOptions()
+	var repo string
+	var dataset string
+	var refresh bool
+	var last int
+	var cacheDir string
+	var noCache bool
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Suggest changed-files rules learned from PR history",
+		Long:  `Mine the changed files and final labels of recently merged PRs and propose, for each label, the directories whose PRs mostly have the label as a labeler.yml fragment, with the precision and recall of each rule. With --dataset, the PR history is saved to and loaded from a JSON file, so that suggestions can be tuned offline.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if last <= 0 {
+				return fmt.Errorf("--last must be greater than 0")
+			}
+			var d *labeler.Dataset
+			if dataset != "" && !refresh {
+				loaded, err := labeler.LoadDataset(dataset)
+				if err != nil && !errors.Is(err, os.ErrNotExist) {
+					return fmt.Errorf("failed to load dataset: %w", err)
+				}
+				d = loaded
+			}
+			if d == nil {
+				repository, err := parser.Repository(parser.RepositoryInput(repo))
+				if err != nil {
+					return fmt.Errorf("error parsing repository: %w", err)
+				}
+				client, err := gh.NewGitHubClientWithRepo(repository)
+				if err != nil {
+					return fmt.Errorf("error creating GitHub client: %w", err)
+				}
+				var cache *labeler.FileCache
+				if !noCache {
+					if cacheDir == "" {
+						cacheDir, err = labeler.DefaultFileCacheDir()
+						if err != nil {
+							return fmt.Errorf("failed to resolve cache directory: %w", err)
+						}
+					}
+					cache = &labeler.FileCache{Dir: cacheDir}
+				}
+				d, err = labeler.FetchDataset(cmd.Context(), client, repository, last, cache)
+				if err != nil {
+					return fmt.Errorf("failed to collect PR history for %s: %w", parser.GetRepositoryFullName(repository), err)
+				}
+				if dataset != "" {
+					if err := d.Save(dataset); err != nil {
+						return fmt.Errorf("failed to save dataset: %w", err)
+					}
+					logger.Info("Saved dataset", "path", dataset, "pullRequests", len(d.PullRequests))
+				}
+			}
+
+			suggestions := labeler.Suggest(d, thresholds)
+			if opts.Exporter != nil {
+				return render.NewRenderer(opts.Exporter).RenderExportedData(suggestions)
+			}
+			_, err := fmt.Fprint(cmd.OutOrStdout(), labeler.FormatSuggestions(suggestions))
+			return err
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&cacheDir, "cache-dir", "", "Directory to cache the changed files of PRs (default: the user cache directory)")
+	f.StringVar(&dataset, "dataset", "", "JSON file to load the PR history from, or to save it to if it does not exist")
+	f.IntVar(&last, "last", 200, "Number of most recently updated closed PRs to collect")
+	f.IntVar(&thresholds.MaxDepth, "max-depth", thresholds.MaxDepth, "Maximum depth of the suggested directories")
+	f.Float64Var(&thresholds.MinPrecision, "min-precision", thresholds.MinPrecision, "Minimum ratio of the PRs touching a directory that have the label")
+	f.IntVar(&thresholds.MinSupport, "min-support", thresholds.MinSupport, "Minimum number of PRs touching a directory that have the label")
+	f.BoolVar(&noCache, "no-cache", false, "Do not cache the changed files of PRs")
+	f.BoolVar(&refresh, "refresh", false, "Collect the PR history again even if the dataset exists")
+	f.StringVarP(&repo, "repo", "R", "", "Target repository in the format 'owner/repo'")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+
+	return cmd
+}
@@ -13,6 +13,7 @@ func NewMilestoneCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(milestone.NewListCmd())
+	cmd.AddCommand(milestone.NewSummaryCmd())
 	return cmd
 }
 
@@ -0,0 +1,56 @@
+package milestone
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
+)
+
+type SummaryOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+func NewSummaryCmd() *cobra.Command {
+	opts := &SummaryOptions{}
+	var colorFlag string
+	var group string
+	var items bool
+	var repo string
+	cmd := &cobra.Command{
+		Use:   "summary <milestone>",
+		Short: "Summarize labels for a milestone",
+		Long:  `Show the number of open and closed issues and PRs for each label in the specified milestone. Labels can be grouped by the prefix of their names, and the issues and PRs can be listed per label.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := args[0]
+			repository, err := parser.Repository(parser.RepositoryInput(repo), parser.RepositoryFromURL(target))
+			if err != nil {
+				return fmt.Errorf("failed to resolve repository: %w", err)
+			}
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("failed to create GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			summary, err := labels.SummarizeMilestone(ctx, client, repository, target, group, items)
+			if err != nil {
+				return fmt.Errorf("failed to summarize labels for milestone %s: %w", target, err)
+			}
+			renderer := labels.NewRenderer(opts.Exporter)
+			renderer.SetColor(colorFlag)
+			return renderer.RenderMilestoneSummary(summary)
+		},
+	}
+	f := cmd.Flags()
+	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
+	f.StringVar(&group, "group", "", "Group labels by the prefix before the separator (e.g. ':' or '/')")
+	f.BoolVar(&items, "items", false, "List the issues and PRs for each label")
+	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+	return cmd
+}
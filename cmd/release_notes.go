@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+type ReleaseNotesOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+// NewReleaseNotesCmd creates a command that generates release notes grouped by labels.
+func NewReleaseNotesCmd() *cobra.Command {
+	opts := &ReleaseNotesOptions{}
+	var configPath string
+	var from string
+	var milestone string
+	var repo string
+	var to string
+	cmd := &cobra.Command{
+		Use:   "release-notes",
+		Short: "Generate release notes grouped by labels",
+		Long:  `Generate release notes from the pull requests merged between two refs (--from and --to) or in a milestone (--milestone), grouped into sections by label. The sections are defined in the same format as GitHub's automatically generated release notes (default: .github/release.yml). The config is read from a local file if it exists, otherwise from the repository.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" && milestone == "" {
+				return fmt.Errorf("either --from or --milestone must be specified")
+			}
+			repository, err := parser.Repository(parser.RepositoryInput(repo))
+			if err != nil {
+				return fmt.Errorf("error parsing repository: %w", err)
+			}
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("error creating GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			if from != "" && to == "" {
+				r, err := gh.GetRepository(ctx, client, repository)
+				if err != nil {
+					return fmt.Errorf("failed to get repository %s: %w", parser.GetRepositoryFullName(repository), err)
+				}
+				to = r.GetDefaultBranch()
+			}
+			cfg, err := labels.LoadReleaseConfig(ctx, client, repository, configPath, to)
+			if err != nil {
+				return fmt.Errorf("failed to load release config: %w", err)
+			}
+
+			var entries []labels.ReleaseNoteEntry
+			if milestone != "" {
+				entries, err = labels.ListMergedPullRequestsInMilestone(ctx, client, repository, milestone)
+				if err != nil {
+					return fmt.Errorf("failed to list pull requests in milestone %s: %w", milestone, err)
+				}
+			} else {
+				entries, err = labels.ListMergedPullRequestsBetween(ctx, client, repository, from, to)
+				if err != nil {
+					return fmt.Errorf("failed to list pull requests between %s and %s: %w", from, to, err)
+				}
+			}
+
+			renderer := labels.NewRenderer(opts.Exporter)
+			return renderer.RenderReleaseNotes(cfg.Group(entries))
+		},
+	}
+	f := cmd.Flags()
+	f.StringVar(&configPath, "config", labels.DefaultReleaseConfigPath, "Path to the release notes config YAML file (local path or path in the repository)")
+	f.StringVar(&from, "from", "", "Tag or ref of the previous release")
+	f.StringVar(&milestone, "milestone", "", "Milestone number or URL to collect pull requests from instead of a range")
+	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	f.StringVar(&to, "to", "", "Tag or ref of the release (default: the default branch)")
+	cmd.MarkFlagsMutuallyExclusive("from", "milestone")
+	cmd.MarkFlagsMutuallyExclusive("to", "milestone")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewReleaseNotesCmd())
+}
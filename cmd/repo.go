@@ -12,8 +12,13 @@ func NewRepoCmd() *cobra.Command {
 		Long:  `Manage repository labels.`,
 	}
 
+	cmd.AddCommand(repo.NewApplyCmd())
 	cmd.AddCommand(repo.NewCopyCmd())
+	cmd.AddCommand(repo.NewDoctorCmd())
+	cmd.AddCommand(repo.NewExportCmd())
 	cmd.AddCommand(repo.NewListCmd())
+	cmd.AddCommand(repo.NewRenameCmd())
+	cmd.AddCommand(repo.NewStatsCmd())
 	cmd.AddCommand(repo.NewSyncCmd())
 
 	return cmd
@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
+)
+
+type ApplyOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+func NewApplyCmd() *cobra.Command {
+	opts := &ApplyOptions{}
+	var colorFlag string
+	var repo string
+	var file string
+	var dryrun bool
+	var prune bool
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a label manifest to a repository",
+		Long:  `Reconcile the repository labels to a declarative manifest file. The plan of labels to create, update, rename (by aliases) and delete is printed before it is applied. Renamed labels keep their associations with issues and pull requests. Labels not in the manifest are deleted only if --prune is specified.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := labels.LoadManifest(file)
+			if err != nil {
+				return fmt.Errorf("failed to load manifest %s: %w", file, err)
+			}
+			repository, err := parser.Repository(parser.RepositoryInput(repo))
+			if err != nil {
+				return fmt.Errorf("error parsing repository: %w", err)
+			}
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("error creating GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			plan, err := labels.ComputePlan(ctx, client, repository, manifest.Labels, labels.DiffOptions{Update: true, Prune: prune})
+			if err != nil {
+				return fmt.Errorf("failed to compute label changes for %s: %w", parser.GetRepositoryFullName(repository), err)
+			}
+
+			renderer := labels.NewRenderer(opts.Exporter)
+			renderer.SetColor(colorFlag)
+			return applyPlans(ctx, renderer, []*labels.Plan{plan}, dryrun)
+		},
+	}
+
+	f := cmd.Flags()
+	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
+	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	f.StringVarP(&file, "file", "f", "", "Path to the label manifest file (YAML, JSON or CSV)")
+	f.BoolVarP(&dryrun, "dryrun", "n", false, "Dry run: show the plan without applying it")
+	f.BoolVar(&prune, "prune", false, "Delete repository labels that are not in the manifest")
+	_ = cmd.MarkFlagRequired("file")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+
+	return cmd
+}
@@ -0,0 +1,127 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh/guardrails"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// bulkOptions holds the flags to target every repository of an owner instead of listing destinations.
+type bulkOptions struct {
+	owner        string
+	all          bool
+	include      []string
+	exclude      []string
+	topics       []string
+	skipArchived bool
+	skipForks    bool
+	concurrency  int
+	stateFile    string
+}
+
+func addBulkFlags(cmd *cobra.Command, o *bulkOptions) {
+	f := cmd.Flags()
+	f.BoolVar(&o.all, "all", false, "Target every repository of the owner specified by --owner")
+	f.IntVar(&o.concurrency, "concurrency", 4, "Number of repositories to process concurrently with --all")
+	f.StringSliceVar(&o.exclude, "exclude", nil, "Regular expression of repository names to exclude with --all")
+	f.StringSliceVar(&o.include, "include", nil, "Regular expression of repository names to include with --all")
+	f.StringVar(&o.owner, "owner", "", "Owner (organization or user) of the destination repositories with --all, in the format '[HOST/]OWNER'")
+	f.BoolVar(&o.skipArchived, "skip-archived", false, "Skip archived repositories with --all")
+	f.BoolVar(&o.skipForks, "skip-forks", false, "Skip forked repositories with --all")
+	f.StringVar(&o.stateFile, "state-file", "", "File to record synced repositories to resume an interrupted run with --all")
+	f.StringSliceVar(&o.topics, "topic", nil, "Target only repositories with any of the topics with --all")
+}
+
+// args validates the positional arguments: destinations are required unless --all is specified.
+func (o *bulkOptions) args(cmd *cobra.Command, args []string) error {
+	if !o.all {
+		return cobra.MinimumNArgs(1)(cmd, args)
+	}
+	if o.owner == "" {
+		return fmt.Errorf("--owner is required with --all")
+	}
+	return cobra.NoArgs(cmd, args)
+}
+
+// targets lists the repositories of the owner selected by the filter flags, excluding the source repository.
+func (o *bulkOptions) targets(ctx context.Context, client *gh.GitHubClient, owner repository.Repository, src repository.Repository) ([]repository.Repository, error) {
+	filter, err := labels.NewTargetFilter(o.include, o.exclude, o.topics, o.skipArchived, o.skipForks)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := gh.ListOwnerRepositories(ctx, client, owner.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories for %s: %w", owner.Owner, err)
+	}
+	excludes := []string{}
+	if owner.Host == src.Host {
+		excludes = append(excludes, parser.GetRepositoryFullName(src))
+	}
+	var targets []repository.Repository
+	for _, fullName := range filter.Select(repos, excludes...) {
+		dst, err := parser.Repository(parser.RepositoryInput(fullName))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing destination repository: %w", err)
+		}
+		dst.Host = owner.Host
+		targets = append(targets, dst)
+	}
+	logger.Info("Selected destination repositories", "owner", owner.Owner, "host", owner.Host, "count", len(targets), "total", len(repos))
+	return targets, nil
+}
+
+// run reflects the source labels to every selected repository and renders the per-repository report.
+// The owner may be on a different host from the source, in the "[HOST/]OWNER" format.
+func (o *bulkOptions) run(ctx context.Context, src repository.Repository, srcLabels []*labels.Label, syncOpts labels.SyncOptions, dryrun bool, renderer *labels.Renderer) error {
+	owner, err := parser.Repository(parser.RepositoryOwnerWithHost(o.owner))
+	if err != nil {
+		return fmt.Errorf("error parsing owner: %w", err)
+	}
+	client, err := gh.NewGitHubClientWithRepo(owner)
+	if err != nil {
+		return fmt.Errorf("error creating GitHub client for %s: %w", owner.Host, err)
+	}
+	targets, err := o.targets(ctx, client, owner, src)
+	if err != nil {
+		return err
+	}
+	var state *labels.SyncState
+	if o.stateFile != "" {
+		state, err = labels.LoadSyncState(o.stateFile)
+		if err != nil {
+			return fmt.Errorf("failed to load state file %s: %w", o.stateFile, err)
+		}
+	}
+	if guardrails.IsReadonly() {
+		logger.Info("Read-only mode: the label changes are planned but not applied")
+	}
+	bulk := &labels.BulkSync{
+		Client:      client,
+		Source:      srcLabels,
+		Options:     syncOpts,
+		Concurrency: o.concurrency,
+		DryRun:      dryrun || guardrails.IsReadonly(),
+		State:       state,
+	}
+	results := bulk.Run(ctx, targets)
+	if err := renderer.RenderSyncResults(results); err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Status == labels.SyncStatusFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to sync labels to %d of %d repositories", failed, len(results))
+	}
+	return nil
+}
@@ -3,21 +3,31 @@ package repo
 import (
 	"fmt"
 
+	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
-	"github.com/srz-zumix/go-gh-extension/pkg/logger"
 	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
 )
 
+type CopyOptions struct {
+	Exporter cmdutil.Exporter
+}
+
 func NewCopyCmd() *cobra.Command {
+	opts := &CopyOptions{}
+	var colorFlag string
+	var dryrun bool
 	var force bool
 	var repo string
+	bulk := &bulkOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "copy <dst-repository...>",
+		Use:   "copy [<dst-repository...> | --owner <owner> --all]",
 		Short: "Copy labels from source repository to destination repository",
-		Long:  `Copy all labels from the source repository to the destination repositories. If a label already exists in the destination, it will be skipped unless --force is specified.`,
-		Args:  cobra.MinimumNArgs(1),
+		Long:  `Copy all labels from the source repository to the destination repositories. If a label already exists in the destination, it will be skipped unless --force is specified. The plan for every destination is computed and printed before any change is applied. With --owner and --all, every repository of the owner (filtered by --include, --exclude, --topic, --skip-archived and --skip-forks) is processed concurrently and a per-repository report is printed. The source and destinations may be on different hosts (e.g. GitHub Enterprise Server and github.com); each host uses the token of the gh CLI authentication for that host.`,
+		Args:  bulk.args,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			src, err := parser.Repository(parser.RepositoryInput(repo))
 			if err != nil {
@@ -29,27 +39,45 @@ func NewCopyCmd() *cobra.Command {
 			}
 
 			ctx := cmd.Context()
+			srcLabels, err := gh.ListLabels(ctx, client, src)
+			if err != nil {
+				return fmt.Errorf("failed to list labels for %s: %w", parser.GetRepositoryFullName(src), err)
+			}
+			renderer := labels.NewRenderer(opts.Exporter)
+			renderer.SetColor(colorFlag)
+			if bulk.all {
+				return bulk.run(ctx, src, srcLabels, labels.CopyOptions(force), dryrun, renderer)
+			}
+
+			var plans []*labels.Plan
 			for _, dstArg := range args {
 				dst, err := parser.Repository(parser.RepositoryInput(dstArg))
 				if err != nil {
 					return fmt.Errorf("error parsing destination repository: %w", err)
 				}
-				if src.Host != dst.Host {
-					return fmt.Errorf("source and destination repositories must be on the same host: %s vs %s", src.Host, dst.Host)
+				dstClient, err := gh.NewGitHubClientWithRepo(dst)
+				if err != nil {
+					return fmt.Errorf("error creating GitHub client for %s: %w", dst.Host, err)
 				}
 
-				if err := gh.CopyLabels(ctx, client, src, dst, force); err != nil {
-					return fmt.Errorf("failed to copy labels to %s: %w", parser.GetRepositoryFullName(dst), err)
+				plan, err := labels.ComputeSyncPlan(ctx, dstClient, dst, srcLabels, labels.CopyOptions(force))
+				if err != nil {
+					return fmt.Errorf("failed to compute label changes for %s: %w", parser.GetRepositoryFullName(dst), err)
 				}
-				logger.Info("Successfully copied labels", "from", parser.GetRepositoryFullName(src), "to", parser.GetRepositoryFullName(dst))
+				plans = append(plans, plan)
 			}
-			return nil
+
+			return applyPlans(ctx, renderer, plans, dryrun)
 		},
 	}
 
 	f := cmd.Flags()
+	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
+	f.BoolVarP(&dryrun, "dryrun", "n", false, "Dry run: show the plan without applying it")
 	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
 	f.BoolVarP(&force, "force", "f", false, "Overwrite existing labels in the destination repository")
+	addBulkFlags(cmd, bulk)
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
 
 	return cmd
 }
@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
+)
+
+type DoctorOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+func NewDoctorCmd() *cobra.Command {
+	opts := &DoctorOptions{}
+	var colorFlag string
+	var repo string
+	var palettePath string
+	var skipUsage bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Find unused, duplicate and inconsistent labels",
+		Long:  `Find labels that are not used by any issue or pull request, near-duplicates (case, whitespace, emoji and prefix variants such as bug, Bug and type: bug), labels without descriptions, and labels whose colors conflict with the palette given by --palette. Near-duplicates come with a suggested repo rename command that merges them into the most used label.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			diagnoseOpts := labels.DiagnoseOptions{}
+			if palettePath != "" {
+				palette, err := labels.LoadPalette(palettePath)
+				if err != nil {
+					return fmt.Errorf("failed to load palette %s: %w", palettePath, err)
+				}
+				diagnoseOpts.Palette = palette
+			}
+			repository, err := parser.Repository(parser.RepositoryInput(repo))
+			if err != nil {
+				return fmt.Errorf("error parsing repository: %w", err)
+			}
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("error creating GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			repoLabels, err := gh.ListLabels(ctx, client, repository)
+			if err != nil {
+				return fmt.Errorf("failed to list labels for %s: %w", parser.GetRepositoryFullName(repository), err)
+			}
+			if !skipUsage {
+				diagnoseOpts.Usage, err = labels.ComputeUsage(ctx, client, repository, repoLabels)
+				if err != nil {
+					return fmt.Errorf("failed to count label usage for %s: %w", parser.GetRepositoryFullName(repository), err)
+				}
+			}
+			findings := labels.Diagnose(repoLabels, diagnoseOpts)
+
+			renderer := labels.NewRenderer(opts.Exporter)
+			renderer.SetColor(colorFlag)
+			return renderer.RenderFindings(parser.GetRepositoryFullName(repository), findings)
+		},
+	}
+
+	f := cmd.Flags()
+	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
+	f.StringVar(&palettePath, "palette", "", "Path to the color palette file (YAML or JSON)")
+	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	f.BoolVar(&skipUsage, "skip-usage", false, "Do not look up label usage (skips the unused check and merge target ranking by usage)")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+
+	return cmd
+}
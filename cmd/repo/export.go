@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+type ExportOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+func NewExportCmd() *cobra.Command {
+	opts := &ExportOptions{}
+	var repo string
+	var outputFormat string
+	var output string
+	var usage bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export repository labels to a manifest",
+		Long:  `Export all labels of the repository as a label manifest in YAML or CSV format (--output-format), or in JSON format (--format json). The output can be applied back with repo apply. Use --usage to include the number of issues and pull requests using each label (informational only, ignored by repo apply).`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repository, err := parser.Repository(parser.RepositoryInput(repo))
+			if err != nil {
+				return fmt.Errorf("error parsing repository: %w", err)
+			}
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("error creating GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			repoLabels, err := gh.ListLabels(ctx, client, repository)
+			if err != nil {
+				return fmt.Errorf("failed to list labels for %s: %w", parser.GetRepositoryFullName(repository), err)
+			}
+			manifest := &labels.Manifest{Labels: labels.NewDefinitions(repoLabels)}
+			if usage {
+				for i := range manifest.Labels {
+					count, err := labels.CountLabelUsage(ctx, client, repository, manifest.Labels[i].Name)
+					if err != nil {
+						return fmt.Errorf("failed to count usage of label %s: %w", manifest.Labels[i].Name, err)
+					}
+					manifest.Labels[i].Usage = labels.Ptr(count)
+				}
+			}
+
+			renderer := labels.NewRenderer(opts.Exporter)
+			var w io.Writer = cmd.OutOrStdout()
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", output, err)
+				}
+				defer f.Close() // nolint
+				w = f
+				renderer.IO.Out = f
+			}
+			if opts.Exporter != nil {
+				err = renderer.RenderExportedData(manifest)
+			} else {
+				err = manifest.Export(w, outputFormat)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to export labels: %w", err)
+			}
+			if output != "" {
+				logger.Info("Exported labels", "repository", parser.GetRepositoryFullName(repository), "file", output, "count", len(manifest.Labels))
+			}
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&output, "output", "o", "", "Write the manifest to the file instead of stdout")
+	cmdutil.StringEnumFlag(cmd, &outputFormat, "output-format", "", labels.ExportFormatYAML, []string{labels.ExportFormatYAML, labels.ExportFormatCSV}, "Manifest format")
+	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	f.BoolVar(&usage, "usage", false, "Include the number of issues and pull requests using each label")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+	cmd.MarkFlagsMutuallyExclusive("format", "output-format")
+
+	return cmd
+}
@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh/guardrails"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// applyPlans renders the plans and applies them unless dryrun is set or the read-only guardrail is enabled.
+// Each plan is applied with the client for the host of its repository.
+func applyPlans(ctx context.Context, renderer *labels.Renderer, plans []*labels.Plan, dryrun bool) error {
+	if err := renderer.RenderPlans(plans); err != nil {
+		return err
+	}
+	if dryrun {
+		return nil
+	}
+	if guardrails.IsReadonly() {
+		logger.Info("Read-only mode: the label changes above were not applied")
+		return nil
+	}
+	for _, plan := range plans {
+		if !plan.HasChanges() {
+			continue
+		}
+		client, err := gh.NewGitHubClientWithRepo(plan.Repo())
+		if err != nil {
+			return fmt.Errorf("error creating GitHub client: %w", err)
+		}
+		if err := plan.Apply(ctx, client); err != nil {
+			return fmt.Errorf("failed to apply label changes to %s: %w", plan.Repository, err)
+		}
+		logger.Info("Successfully applied label changes", "repository", plan.Repository, "summary", plan.Summary())
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh/guardrails"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+type RenameOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+func NewRenameCmd() *cobra.Command {
+	opts := &RenameOptions{}
+	var dryrun bool
+	var repo string
+
+	cmd := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a label or merge it into another label",
+		Long:  `Rename a label in the repository. If the new label already exists, the old label is merged into it: every issue, pull request and discussion carrying the old label is relabeled, and then the old label is deleted. The old label is kept if any item fails or fewer items than counted are relabeled (the search index may lag behind the label changes), so running the command again resumes the merge.`,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, to := args[0], args[1]
+			repository, err := parser.Repository(parser.RepositoryInput(repo))
+			if err != nil {
+				return fmt.Errorf("error parsing repository: %w", err)
+			}
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("error creating GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			plan, err := labels.PlanRename(ctx, client, repository, from, to)
+			if err != nil {
+				return fmt.Errorf("failed to plan rename of label %s: %w", from, err)
+			}
+
+			renderer := labels.NewRenderer(opts.Exporter)
+			if err := renderer.RenderRenamePlan(plan); err != nil {
+				return err
+			}
+			if dryrun {
+				return nil
+			}
+			if guardrails.IsReadonly() {
+				logger.Info("Read-only mode: the label was not renamed")
+				return nil
+			}
+			if err := plan.Apply(ctx, client); err != nil {
+				return fmt.Errorf("failed to %s label %s to %s: %w", plan.Action, plan.From, plan.To, err)
+			}
+			logger.Info("Successfully renamed label", "repository", plan.Repository, "action", plan.Action, "from", plan.From, "to", plan.To)
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVarP(&dryrun, "dryrun", "n", false, "Dry run: show the number of affected items without renaming")
+	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+
+	return cmd
+}
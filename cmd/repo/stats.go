@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
+)
+
+type StatsOptions struct {
+	Exporter cmdutil.Exporter
+}
+
+func NewStatsCmd() *cobra.Command {
+	opts := &StatsOptions{}
+	var colorFlag string
+	var repo string
+	var unused bool
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show label usage statistics",
+		Long:  `Show the number of open and closed issues and pull requests for each label in the repository, and when the most recently updated item with the label was last updated (any comment or edit updates an item, so this is not when the label was applied). Counts are taken from the search API, which is rate limited; the command waits and retries when the limit is exceeded.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repository, err := parser.Repository(parser.RepositoryInput(repo))
+			if err != nil {
+				return fmt.Errorf("error parsing repository: %w", err)
+			}
+			client, err := gh.NewGitHubClientWithRepo(repository)
+			if err != nil {
+				return fmt.Errorf("error creating GitHub client: %w", err)
+			}
+			ctx := cmd.Context()
+			stats, err := labels.ComputeStats(ctx, client, repository)
+			if err != nil {
+				return fmt.Errorf("failed to compute label stats for %s: %w", parser.GetRepositoryFullName(repository), err)
+			}
+			if unused {
+				var filtered []*labels.LabelStats
+				for _, s := range stats {
+					if s.IsUnused() {
+						filtered = append(filtered, s)
+					}
+				}
+				stats = filtered
+			}
+
+			renderer := labels.NewRenderer(opts.Exporter)
+			renderer.SetColor(colorFlag)
+			return renderer.RenderLabelStats(stats)
+		},
+	}
+
+	f := cmd.Flags()
+	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
+	f.StringVarP(&repo, "repo", "R", "", "Repository in the format 'owner/repo'")
+	f.BoolVar(&unused, "unused", false, "Show only labels that are not used by any issue or pull request")
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
+
+	return cmd
+}
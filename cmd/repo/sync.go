@@ -3,21 +3,31 @@ package repo
 import (
 	"fmt"
 
+	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/spf13/cobra"
+	"github.com/srz-zumix/gh-label-kit/labels"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
-	"github.com/srz-zumix/go-gh-extension/pkg/logger"
 	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
 )
 
+type SyncOptions struct {
+	Exporter cmdutil.Exporter
+}
+
 func NewSyncCmd() *cobra.Command {
+	opts := &SyncOptions{}
+	var colorFlag string
+	var dryrun bool
 	var force bool
 	var repo string
+	bulk := &bulkOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "sync <dst-repository...>",
+		Use:   "sync [<dst-repository...> | --owner <owner> --all]",
 		Short: "Sync labels from source repository to destination repository",
-		Long:  `Sync all labels from the source repository to the destination repositories. If a label already exists in the destination, it will be updated if --force is specified.`,
-		Args:  cobra.MinimumNArgs(1),
+		Long:  `Sync all labels from the source repository to the destination repositories. Missing labels are created and differing labels are updated. Labels that exist only in the destination are deleted if they are not used by any issue or pull request, or always if --force is specified. The plan for every destination is computed and printed before any change is applied. With --owner and --all, every repository of the owner (filtered by --include, --exclude, --topic, --skip-archived and --skip-forks) is processed concurrently and a per-repository report is printed. The source and destinations may be on different hosts (e.g. GitHub Enterprise Server and github.com); each host uses the token of the gh CLI authentication for that host.`,
+		Args:  bulk.args,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			src, err := parser.Repository(parser.RepositoryInput(repo))
 			if err != nil {
@@ -28,26 +38,44 @@ func NewSyncCmd() *cobra.Command {
 				return fmt.Errorf("error creating GitHub client: %w", err)
 			}
 			ctx := cmd.Context()
+			srcLabels, err := gh.ListLabels(ctx, client, src)
+			if err != nil {
+				return fmt.Errorf("failed to list labels for %s: %w", parser.GetRepositoryFullName(src), err)
+			}
+			renderer := labels.NewRenderer(opts.Exporter)
+			renderer.SetColor(colorFlag)
+			if bulk.all {
+				return bulk.run(ctx, src, srcLabels, labels.MirrorOptions(force), dryrun, renderer)
+			}
+
+			var plans []*labels.Plan
 			for _, dstArg := range args {
 				dst, err := parser.Repository(parser.RepositoryInput(dstArg))
 				if err != nil {
 					return fmt.Errorf("error parsing destination repository: %w", err)
 				}
-				if src.Host != dst.Host {
-					return fmt.Errorf("source and destination repositories must be on the same host: %s vs %s", src.Host, dst.Host)
+				dstClient, err := gh.NewGitHubClientWithRepo(dst)
+				if err != nil {
+					return fmt.Errorf("error creating GitHub client for %s: %w", dst.Host, err)
 				}
-				if err := gh.SyncLabels(ctx, client, src, dst, force); err != nil {
-					return fmt.Errorf("failed to sync labels to %s: %w", parser.GetRepositoryFullName(dst), err)
+				plan, err := labels.ComputeSyncPlan(ctx, dstClient, dst, srcLabels, labels.MirrorOptions(force))
+				if err != nil {
+					return fmt.Errorf("failed to compute label changes for %s: %w", parser.GetRepositoryFullName(dst), err)
 				}
-				logger.Info("Successfully synced labels", "from", parser.GetRepositoryFullName(src), "to", parser.GetRepositoryFullName(dst))
+				plans = append(plans, plan)
 			}
-			return nil
+
+			return applyPlans(ctx, renderer, plans, dryrun)
 		},
 	}
 
 	f := cmd.Flags()
-	f.BoolVarP(&force, "force", "f", false, "Overwrite existing labels in the destination repository")
+	cmdutil.StringEnumFlag(cmd, &colorFlag, "color", "", render.ColorFlagAuto, render.ColorFlags, "Use color in diff output")
+	f.BoolVarP(&dryrun, "dryrun", "n", false, "Dry run: show the plan without applying it")
+	f.BoolVarP(&force, "force", "f", false, "Delete labels that exist only in the destination even if they are in use")
 	f.StringVarP(&repo, "repo", "R", "", "The repository in the format 'owner/repo'")
+	addBulkFlags(cmd, bulk)
+	cmdutil.AddFormatFlags(cmd, &opts.Exporter)
 
 	return cmd
 }
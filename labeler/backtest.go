@@ -0,0 +1,175 @@
+package labeler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v84/github"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// FileCache caches the changed files of pull requests on the local disk, keyed by the head commit.
+// A nil FileCache does not cache.
+type FileCache struct {
+	Dir string
+}
+
+// DefaultFileCacheDir returns the default directory of the changed files cache in the user cache directory.
+func DefaultFileCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-label-kit", "pr-files"), nil
+}
+
+func (c *FileCache) path(repo repository.Repository, pr *PullRequest) string {
+	return filepath.Join(c.Dir, repo.Host, repo.Owner, repo.Name, fmt.Sprintf("%d-%s.json", pr.GetNumber(), pr.GetHead().GetSHA()))
+}
+
+func (c *FileCache) load(repo repository.Repository, pr *PullRequest) ([]*CommitFile, bool) {
+	if c == nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(repo, pr))
+	if err != nil {
+		return nil, false
+	}
+	var files []*CommitFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		logger.Debug("Ignoring broken cache entry", "pr", pr.GetNumber(), "error", err)
+		return nil, false
+	}
+	return files, true
+}
+
+func (c *FileCache) store(repo repository.Repository, pr *PullRequest, files []*CommitFile) error {
+	if c == nil {
+		return nil
+	}
+	path := c.path(repo, pr)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ListPullRequestFiles returns the changed files of the pull request from the cache, or fetches and caches them.
+func (c *FileCache) ListPullRequestFiles(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, pr *PullRequest) ([]*CommitFile, error) {
+	if files, ok := c.load(repo, pr); ok {
+		logger.Debug("Loaded PR files from cache", "pr", pr.GetNumber(), "files", len(files))
+		return files, nil
+	}
+	files, err := gh.ListPullRequestFiles(ctx, g, repo, pr.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+	if err := c.store(repo, pr, files); err != nil {
+		logger.Warn("Failed to cache PR files", "pr", pr.GetNumber(), "error", err)
+	}
+	return files, nil
+}
+
+// ListRecentClosedPullRequests lists up to limit merged or closed pull requests, most recently updated first.
+func ListRecentClosedPullRequests(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, limit int) ([]*PullRequest, error) {
+	var prs []*PullRequest
+	opts := &github.PullRequestListOptions{
+		State:       "closed",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: min(limit, 100)},
+	}
+	for len(prs) < limit {
+		page, resp, err := g.GetClient().PullRequests.List(ctx, repo.Owner, repo.Name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+		prs = append(prs, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	if len(prs) > limit {
+		prs = prs[:limit]
+	}
+	logger.Debug("Listed closed pull requests", "count", len(prs))
+	return prs, nil
+}
+
+// Backtest replays the config over the pull requests and reports, per label, the pull requests that would gain or lose it
+// compared with the baseline config, or with their actual labels if baseline is nil.
+// Only the labels in the configs are reported, since other labels are not managed by the labeler.
+// Command conditions are run with the command options, as in the labeler itself.
+func Backtest(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, cfg, baseline LabelerConfig, prs []*PullRequest, cache *FileCache, commandOptions CommandOptions) ([]*labels.LabelImpact, error) {
+	names := slices.Collect(maps.Keys(cfg))
+	if baseline != nil {
+		names = append(names, slices.Collect(maps.Keys(baseline))...)
+	}
+	matcher := NewMatcher(ctx, g)
+	matcher.SetCommandOptions(commandOptions)
+	// Listed PRs have no line counts, which when expressions and commands may use
+	fetchDetails := cfg.usesPullRequestDetails() || baseline.usesPullRequestDetails()
+	if fetchDetails {
+		logger.Info("Fetching each PR, since when expressions or commands may use its line counts", "count", len(prs))
+	}
+	cases := make([]labels.ImpactCase, 0, len(prs))
+	var errs []error
+	for _, pr := range prs {
+		if fetchDetails {
+			detail, err := gh.GetPullRequest(ctx, g, repo, pr.GetNumber())
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get PR #%d: %w", pr.GetNumber(), err))
+				continue
+			}
+			pr = detail
+		}
+		files, err := cache.ListPullRequestFiles(ctx, g, repo, pr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var before []string
+		if baseline != nil {
+			before = backtestLabels(matcher.CheckMatchConfigs(baseline, files, pr), nil)
+		} else {
+			for _, l := range pr.Labels {
+				before = append(before, l.GetName())
+			}
+		}
+		cases = append(cases, labels.ImpactCase{
+			Number: pr.GetNumber(),
+			Before: before,
+			After:  backtestLabels(matcher.CheckMatchConfigs(cfg, files, pr), before),
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return labels.ComputeImpact(names, cases), nil
+}
+
+// backtestLabels returns the labels of the PR after matching: the matched labels, and the labels whose conditions failed
+// to evaluate if the PR had them before, since the labeler neither adds nor removes them.
+func backtestLabels(result MatchResult, before []string) []string {
+	after := slices.Clone(result.Matched)
+	for _, label := range result.Failed {
+		if slices.Contains(before, label) {
+			after = append(after, label)
+		}
+	}
+	return after
+}
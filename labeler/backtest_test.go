@@ -0,0 +1,68 @@
+package labeler
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+func TestFileCache(t *testing.T) {
+	cache := &FileCache{Dir: t.TempDir()}
+	repo := repository.Repository{Host: "github.com", Owner: "owner", Name: "repo"}
+	pr := &PullRequest{Number: Ptr(1), Head: &PullRequestBranch{SHA: Ptr("abc")}}
+
+	if _, ok := cache.load(repo, pr); ok {
+		t.Fatalf("cache should be empty")
+	}
+	files := []*CommitFile{{Filename: Ptr("docs/README.md"), Status: Ptr("modified")}}
+	if err := cache.store(repo, pr, files); err != nil {
+		t.Fatalf("store error: %v", err)
+	}
+	got, ok := cache.load(repo, pr)
+	if !ok || len(got) != 1 || got[0].GetFilename() != "docs/README.md" {
+		t.Errorf("unexpected cached files: %v", got)
+	}
+
+	pr.Head.SHA = Ptr("def")
+	if _, ok := cache.load(repo, pr); ok {
+		t.Errorf("cache should be keyed by head commit")
+	}
+
+	var nilCache *FileCache
+	if err := nilCache.store(repo, pr, files); err != nil {
+		t.Errorf("nil cache should not fail: %v", err)
+	}
+	if _, ok := nilCache.load(repo, pr); ok {
+		t.Errorf("nil cache should not hit")
+	}
+}
+
+func TestBacktestLabels(t *testing.T) {
+	result := MatchResult{Matched: []string{"api"}, Unmatched: []string{"docs"}, Failed: []string{"large", "public-api"}}
+	got := backtestLabels(result, []string{"docs", "large"})
+	if want := []string{"api", "large"}; !slices.Equal(got, want) {
+		t.Errorf("backtestLabels() = %v, want %v", got, want)
+	}
+}
+
+func TestUsesPullRequestDetails(t *testing.T) {
+	for content, want := range map[string]bool{
+		"docs:\n  - changed-files:\n    - any-glob-to-any-file: 'docs/**'\n": false,
+		"large:\n  - when: 'pr.additions > 300'\n":                           true,
+		"api:\n  - all:\n    - not:\n      - command: ./check\n":             true,
+	} {
+		cfg, err := LoadConfigFromReader(strings.NewReader(content), true)
+		if err != nil {
+			t.Fatalf("LoadConfig error: %v", err)
+		}
+		if got := cfg.usesPullRequestDetails(); got != want {
+			t.Errorf("usesPullRequestDetails() = %v, want %v for\n%s", got, want, content)
+		}
+	}
+	var nilConfig LabelerConfig
+	if nilConfig.usesPullRequestDetails() {
+		t.Errorf("nil config should not use PR details")
+	}
+}
@@ -0,0 +1,171 @@
+package labeler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v84/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// CodeownersPaths are the locations of the CODEOWNERS file relative to the repository root, in the order GitHub looks them up.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeownersRule is a line of a CODEOWNERS file.
+type CodeownersRule struct {
+	Pattern string
+	Owners  []string
+	glob    string
+}
+
+// Codeowners is a parsed CODEOWNERS file. A nil Codeowners has no rules.
+type Codeowners struct {
+	Rules []CodeownersRule
+}
+
+// ParseCodeowners parses a CODEOWNERS file. Comments and blank lines are skipped. A pattern without owners is kept as a rule
+// with no owners, since it still takes precedence over the previous patterns and makes the files unowned, as on GitHub.
+func ParseCodeowners(r io.Reader) (*Codeowners, error) {
+	c := &Codeowners{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		c.Rules = append(c.Rules, CodeownersRule{Pattern: fields[0], Owners: fields[1:], glob: gitPatternGlob(fields[0])})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+	return c, nil
+}
+
+// LoadCodeowners loads the CODEOWNERS file of the repository at root. It returns nil without error if there is none.
+func LoadCodeowners(root string) (*Codeowners, error) {
+	for _, p := range CodeownersPaths {
+		f, err := os.Open(filepath.Join(root, filepath.FromSlash(p)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", p, err)
+		}
+		defer f.Close()
+		c, err := ParseCodeowners(f)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("CODEOWNERS loaded successfully", "path", p, "rules", len(c.Rules))
+		return c, nil
+	}
+	return nil, nil
+}
+
+// FetchCodeowners loads the CODEOWNERS file of the repository at the ref. It returns nil without error if there is none.
+func FetchCodeowners(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, ref string) (*Codeowners, error) {
+	for _, p := range CodeownersPaths {
+		fileContent, err := gh.GetRepositoryFileContent(ctx, g, repo, p, &ref)
+		if err != nil {
+			var errResp *github.ErrorResponse
+			if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return nil, err
+		}
+		if fileContent == nil {
+			continue
+		}
+		content, err := fileContent.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", p, err)
+		}
+		c, err := ParseCodeowners(strings.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("CODEOWNERS loaded from repository", "owner", repo.Owner, "repo", repo.Name, "path", p, "ref", ref, "rules", len(c.Rules))
+		return c, nil
+	}
+	logger.Debug("CODEOWNERS not found in repository", "owner", repo.Owner, "repo", repo.Name, "ref", ref)
+	return nil, nil
+}
+
+// matches reports whether the rule's pattern matches the file, or a directory containing it.
+// A pattern whose last segment is a wildcard, such as "docs/*", only matches the entries it names and not their contents,
+// as on GitHub. Unlike labeler globs, CODEOWNERS patterns have no extglob and always match hidden files.
+func (r *CodeownersRule) matches(file string) bool {
+	if ok, err := doublestar.Match(r.glob, file); err == nil && ok {
+		return true
+	}
+	if strings.ContainsAny(path.Base(r.glob), "*?[") {
+		return false
+	}
+	ok, err := doublestar.Match(r.glob+"/**", file)
+	return err == nil && ok
+}
+
+// rule returns the last rule matching the file, as the last matching pattern takes precedence, or nil if none matches.
+func (c *Codeowners) rule(file string) *CodeownersRule {
+	if c == nil {
+		return nil
+	}
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		r := &c.Rules[i]
+		if r.matches(file) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Owners returns the owners of the file.
+func (c *Codeowners) Owners(file string) []string {
+	if r := c.rule(file); r != nil {
+		return r.Owners
+	}
+	return nil
+}
+
+// normalizeOwner returns the owner in lower case with a leading "@", unless it is an email address.
+func normalizeOwner(owner string) string {
+	owner = strings.ToLower(owner)
+	if !strings.Contains(owner, "@") {
+		owner = "@" + owner
+	}
+	return owner
+}
+
+// OwnedBy reports whether any of the files is owned by any of the owners. Owners are compared case-insensitively,
+// and the leading "@" of a user or team may be omitted.
+func (c *Codeowners) OwnedBy(files []string, owners []string) bool {
+	wanted := make([]string, len(owners))
+	for i, o := range owners {
+		wanted[i] = normalizeOwner(o)
+	}
+	for _, f := range files {
+		for _, o := range c.Owners(f) {
+			if slices.Contains(wanted, normalizeOwner(o)) {
+				logger.Debug("Codeowner matched", "file", f, "owner", o)
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package labeler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testCodeowners = `# Default owners
+*            @org/maintainers
+
+/api/        @org/backend # API team
+/api/handler/user.go @alice
+*.ts         @org/frontend
+tools/gen/   @bob @carol
+docs
+`
+
+func TestParseCodeowners(t *testing.T) {
+	c, err := ParseCodeowners(strings.NewReader(testCodeowners))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.Rules) != 6 {
+		t.Fatalf("expected 6 rules (patterns without owners kept), got %+v", c.Rules)
+	}
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{file: "api/server.go", want: []string{"@org/backend"}},
+		{file: "api/handler/user.go", want: []string{"@alice"}},
+		{file: "web/app.ts", want: []string{"@org/frontend"}},
+		{file: "tools/gen/main.go", want: []string{"@bob", "@carol"}},
+		{file: "README.md", want: []string{"@org/maintainers"}},
+		// A pattern without owners is the last match and makes the file unowned
+		{file: "docs/index.md", want: []string{}},
+	}
+	for _, tt := range tests {
+		if got := c.Owners(tt.file); !slices.Equal(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+
+	// A trailing wildcard segment does not own the contents of subdirectories
+	c, err = ParseCodeowners(strings.NewReader("* @all\ndocs/* @docs\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for file, want := range map[string]string{"docs/index.md": "@docs", "docs/build/x.md": "@all"} {
+		if got := c.Owners(file); !slices.Equal(got, []string{want}) {
+			t.Errorf("Owners(%q) = %v, want [%s]", file, got, want)
+		}
+	}
+
+	var nilOwners *Codeowners
+	if got := nilOwners.Owners("api/server.go"); got != nil {
+		t.Errorf("nil Codeowners should have no owners, got %v", got)
+	}
+}
+
+func TestLoadCodeowners(t *testing.T) {
+	dir := t.TempDir()
+	c, err := LoadCodeowners(dir)
+	if err != nil || c != nil {
+		t.Fatalf("expected nil without CODEOWNERS, got %+v, %v", c, err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte(testCodeowners), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err = LoadCodeowners(dir)
+	if err != nil || c == nil || len(c.Rules) != 6 {
+		t.Fatalf("unexpected result: %+v, %v", c, err)
+	}
+}
+
+func TestCodeownersOwnedBy(t *testing.T) {
+	c, err := ParseCodeowners(strings.NewReader(testCodeowners))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		files  []string
+		owners []string
+		want   bool
+	}{
+		{files: []string{"api/server.go"}, owners: []string{"@org/backend"}, want: true},
+		{files: []string{"api/server.go"}, owners: []string{"ORG/Backend"}, want: true},
+		// The last matching rule wins, so the team does not own the file with a more specific owner.
+		{files: []string{"api/handler/user.go"}, owners: []string{"@org/backend"}, want: false},
+		{files: []string{"README.md", "api/handler/user.go"}, owners: []string{"alice"}, want: true},
+		{files: []string{"docs/index.md"}, owners: []string{"@org/backend", "@org/frontend"}, want: false},
+		{files: []string{"docs/index.md"}, owners: []string{"@org/maintainers"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := c.OwnedBy(tt.files, tt.owners); got != tt.want {
+			t.Errorf("OwnedBy(%v, %v) = %v, want %v", tt.files, tt.owners, got, tt.want)
+		}
+	}
+}
+
+func TestCheckMatchConfigs_Codeowner(t *testing.T) {
+	content := `team:payments:
+  - codeowner: "@org/backend"
+frontend-or-bob:
+  - codeowner: ["@org/frontend", "@bob"]
+backend-docs:
+  - all:
+    - codeowner: "@org/backend"
+    - changed-files:
+      - any-glob-to-any-file: "docs/**"
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(content), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c, err := ParseCodeowners(strings.NewReader(testCodeowners))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pr := &PullRequest{
+		Base:   &PullRequestBranch{Ref: Ptr("main")},
+		Head:   &PullRequestBranch{Ref: Ptr("feature")},
+		Labels: []*Label{},
+	}
+	files := []*CommitFile{{Filename: Ptr("api/server.go")}, {Filename: Ptr("README.md")}}
+	matcher := NewMatcher(context.TODO(), nil)
+	matcher.SetCodeowners(c)
+	result := matcher.CheckMatchConfigs(cfg, files, pr)
+	if !slices.Equal(result.Matched, []string{"team:payments"}) {
+		t.Errorf("matched = %v, want [team:payments]", result.Matched)
+	}
+
+	files = append(files, &CommitFile{Filename: Ptr("tools/gen/main.go")}, &CommitFile{Filename: Ptr("docs/index.md")})
+	result = matcher.CheckMatchConfigs(cfg, files, pr)
+	if !slices.Equal(result.Matched, []string{"backend-docs", "frontend-or-bob", "team:payments"}) {
+		t.Errorf("matched = %v", result.Matched)
+	}
+
+	// Without CODEOWNERS, no codeowner rule matches.
+	result = NewMatcher(context.TODO(), nil).CheckMatchConfigs(cfg, files, pr)
+	if len(result.Matched) != 0 {
+		t.Errorf("matched = %v, want none", result.Matched)
+	}
+}
@@ -0,0 +1,174 @@
+package labeler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/shlex"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh/guardrails"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// DefaultCommandTimeout is the default time limit of an external command.
+const DefaultCommandTimeout = 10 * time.Second
+
+// ReadOnlyEnv is the environment variable set to "true" for external commands in read-only mode.
+const ReadOnlyEnv = "GH_LABEL_KIT_READ_ONLY"
+
+// commandBaseEnv are the environment variables always passed to external commands. Other variables, such as tokens,
+// are only passed if they are listed in CommandOptions.Env.
+var commandBaseEnv = []string{"PATH", "HOME", "USER", "TMPDIR", "TEMP", "TMP", "LANG", "LC_ALL", "SYSTEMROOT"}
+
+// CommandOptions configures how external commands are run.
+type CommandOptions struct {
+	// Allow enables the command conditions of the config. Commands given on the command line always run.
+	Allow bool
+	// Timeout is the time limit of each command (default: DefaultCommandTimeout).
+	Timeout time.Duration
+	// Env are the names of additional environment variables passed to commands.
+	Env []string
+}
+
+// commandInput is the PR context written to the standard input of external commands as JSON.
+type commandInput struct {
+	*whenPullRequest
+	Files []string `json:"files"`
+}
+
+// commandResult is the outcome of an external command that ran to completion.
+type commandResult struct {
+	ExitCode int
+	Stdout   []byte
+}
+
+// splitCommand splits the command line into the program and its arguments. No shell is involved.
+func splitCommand(command string) ([]string, error) {
+	args, err := shlex.Split(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command %q: %w", command, err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("invalid command %q: empty command", command)
+	}
+	return args, nil
+}
+
+// commandEnv returns the environment of external commands.
+func commandEnv(names []string) []string {
+	var env []string
+	for _, name := range slices.Concat(commandBaseEnv, names) {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return append(env, ReadOnlyEnv+"="+strconv.FormatBool(guardrails.IsReadonly()))
+}
+
+// run runs the command with the PR context on its standard input. It fails if the command cannot be started,
+// times out, or is killed; a non-zero exit code is returned in the result.
+func (o CommandOptions) run(ctx context.Context, command string, changedFiles []*CommitFile, pr *PullRequest) (*commandResult, error) {
+	args, err := splitCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	input, err := json.Marshal(commandInput{whenPullRequest: newWhenPullRequest(pr), Files: filenames(changedFiles)})
+	if err != nil {
+		return nil, err
+	}
+	timeout := o.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = commandEnv(o.Env)
+	// Do not wait for processes started by the command that keep its output open after it is killed
+	cmd.WaitDelay = time.Second
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("command %q timed out after %s", command, timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		logger.Debug("Command exited", "command", command, "pr", pr.GetNumber(), "exitCode", exitErr.ExitCode(), "stderr", stderr.String())
+		return &commandResult{ExitCode: exitErr.ExitCode(), Stdout: stdout.Bytes()}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to run command %q: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	logger.Debug("Command succeeded", "command", command, "pr", pr.GetNumber())
+	return &commandResult{Stdout: stdout.Bytes()}, nil
+}
+
+// matchLabelerRuleCommand runs the rule's command and matches if it exits with 0. It does not match if it exits with 1,
+// and fails for any other exit code, or if commands are not allowed.
+// Results are cached per command, PR and changed files, since labels may share a command.
+func (m *Matcher) matchLabelerRuleCommand(r LabelerRule, changedFiles []*CommitFile, pr *PullRequest) matchState {
+	if !m.commandOptions.Allow {
+		if _, warned := m.commandResults[r.Command]; !warned {
+			logger.Warn("Skipping command condition, use --allow-commands to run it", "command", r.Command)
+			m.commandResults[r.Command] = stateFailed
+		}
+		return stateFailed
+	}
+	key := fmt.Sprintf("%s#%d#%s", r.Command, pr.GetNumber(), strings.Join(filenames(changedFiles), "\n"))
+	if state, ok := m.commandResults[key]; ok {
+		return state
+	}
+	state := stateFailed
+	result, err := m.commandOptions.run(m.ctx, r.Command, changedFiles, pr)
+	switch {
+	case err != nil:
+		logger.Warn("Command condition failed", "pr", pr.GetNumber(), "error", err)
+	case result.ExitCode == 0:
+		state = stateMatched
+	case result.ExitCode == 1:
+		state = stateUnmatched
+	default:
+		logger.Warn("Command condition failed", "command", r.Command, "pr", pr.GetNumber(), "exitCode", result.ExitCode)
+	}
+	m.commandResults[key] = state
+	return state
+}
+
+// RunLabelCommands runs each command with the PR context and returns the labels they print, one per line, without duplicates.
+// Label commands are given on the command line, so they run even if command conditions are not allowed.
+func (m *Matcher) RunLabelCommands(commands []string, changedFiles []*CommitFile, pr *PullRequest) ([]string, error) {
+	var labels []string
+	for _, command := range commands {
+		result, err := m.commandOptions.run(m.ctx, command, changedFiles, pr)
+		if err != nil {
+			return nil, err
+		}
+		if result.ExitCode != 0 {
+			return nil, fmt.Errorf("command %q exited with %d", command, result.ExitCode)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(result.Stdout))
+		for scanner.Scan() {
+			if label := strings.TrimSpace(scanner.Text()); label != "" && !slices.Contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+	logger.Debug("Label commands completed", "pr", pr.GetNumber(), "labels", labels)
+	return labels, nil
+}
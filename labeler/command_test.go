@@ -0,0 +1,134 @@
+package labeler
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const commandConfig = `
+alice:
+  - command: sh -c 'grep -q "\"author\":\"alice\""'
+api:
+  - all:
+    - base-branch: main
+    - command: sh -c 'grep -q "\"files\":\[\"api/"'
+broken:
+  - command: sh -c 'exit 2'
+`
+
+func commandPullRequest(author string) *PullRequest {
+	return &PullRequest{
+		Number: Ptr(1),
+		Base:   &PullRequestBranch{Ref: Ptr("main")},
+		Head:   &PullRequestBranch{Ref: Ptr("feature")},
+		User:   &User{Login: Ptr(author)},
+	}
+}
+
+func TestCheckMatchConfigs_Command(t *testing.T) {
+	cfg, err := LoadConfigFromReader(strings.NewReader(commandConfig), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	files := []*CommitFile{{Filename: Ptr("api/server.go")}}
+	tests := []struct {
+		name   string
+		allow  bool
+		author string
+		want   []string
+	}{
+		{name: "allowed", allow: true, author: "alice", want: []string{"alice", "api"}},
+		{name: "other author", allow: true, author: "bob", want: []string{"api"}},
+		{name: "not allowed", allow: false, author: "alice", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatcher(context.TODO(), nil)
+			m.SetCommandOptions(CommandOptions{Allow: tt.allow})
+			result := m.CheckMatchConfigs(cfg, files, commandPullRequest(tt.author))
+			if !slices.Equal(result.Matched, tt.want) {
+				t.Errorf("matched = %v, want %v", result.Matched, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckMatchConfigs_CommandTimeout(t *testing.T) {
+	cfg, err := LoadConfigFromReader(strings.NewReader("slow:\n  - command: sleep 5\n"), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	m := NewMatcher(context.TODO(), nil)
+	m.SetCommandOptions(CommandOptions{Allow: true, Timeout: 100 * time.Millisecond})
+	start := time.Now()
+	result := m.CheckMatchConfigs(cfg, nil, commandPullRequest("alice"))
+	if len(result.Matched) != 0 {
+		t.Errorf("matched = %v, want none", result.Matched)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("command was not stopped by the timeout, took %s", elapsed)
+	}
+}
+
+func TestCheckMatchConfigs_CommandEnv(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "secret")
+	cfg, err := LoadConfigFromReader(strings.NewReader(`token:
+  - command: sh -c 'test -n "$GITHUB_TOKEN"'
+`), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	for _, tt := range []struct {
+		env  []string
+		want bool
+	}{
+		{env: nil, want: false},
+		{env: []string{"GITHUB_TOKEN"}, want: true},
+	} {
+		m := NewMatcher(context.TODO(), nil)
+		m.SetCommandOptions(CommandOptions{Allow: true, Env: tt.env})
+		result := m.CheckMatchConfigs(cfg, nil, commandPullRequest("alice"))
+		if got := result.IsMatched("token"); got != tt.want {
+			t.Errorf("env %v: matched = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestRunLabelCommands(t *testing.T) {
+	m := NewMatcher(context.TODO(), nil)
+	got, err := m.RunLabelCommands([]string{
+		`sh -c 'echo api; echo; echo "  go  "'`,
+		`sh -c 'grep -q "\"title\":\"Add API\"" && echo api && echo public-api'`,
+	}, nil, &PullRequest{Title: Ptr("Add API")})
+	if err != nil {
+		t.Fatalf("RunLabelCommands error: %v", err)
+	}
+	if want := []string{"api", "go", "public-api"}; !slices.Equal(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+
+	if _, err := m.RunLabelCommands([]string{"sh -c 'exit 1'"}, nil, &PullRequest{}); err == nil {
+		t.Errorf("expected an error for a failing label command")
+	}
+}
+
+func TestMatchResult_AddMatched(t *testing.T) {
+	r := MatchResult{Matched: []string{"b"}, Unmatched: []string{"a", "c"}}
+	got := r.AddMatched([]string{"a", "b", "d"})
+	if !slices.Equal(got.Matched, []string{"a", "b", "d"}) || !slices.Equal(got.Unmatched, []string{"c"}) {
+		t.Errorf("AddMatched = %+v", got)
+	}
+	if !slices.Equal(r.Matched, []string{"b"}) || !slices.Equal(r.Unmatched, []string{"a", "c"}) {
+		t.Errorf("AddMatched modified the original result: %+v", r)
+	}
+}
+
+func TestLoadConfig_InvalidCommand(t *testing.T) {
+	_, err := LoadConfigFromReader(strings.NewReader("bad:\n  - command: \"sh -c 'exit\"\n"), false)
+	if err == nil || !strings.Contains(err.Error(), `label "bad"`) {
+		t.Errorf("expected an error naming the label, got %v", err)
+	}
+}
@@ -13,6 +13,10 @@ type LabelerLabelConfig struct {
 	Color       string
 	Description string
 	Codeowners  []string
+	// RespectManual keeps the label as a human last added or removed it on the PR.
+	RespectManual bool
+	// IgnoreFiles are the globs of changed files removed before the rules of the label are evaluated.
+	IgnoreFiles []string
 }
 
 type LabelerMatch struct {
@@ -25,24 +29,41 @@ type labelerYamlConfig map[string][]labelerYamlMatch
 
 // LabelerMatch supports per-label color key (actions/labeler v5 style)
 type labelerYamlMatch struct {
-	Any                []LabelerRule      `yaml:"any,omitempty"`
-	All                []LabelerRule      `yaml:"all,omitempty"`
-	ChangedFiles       []ChangedFilesRule `yaml:"changed-files,omitempty"`
-	AllFilesToAnyGlob  StringOrSlice      `yaml:"all-files-to-any-glob,omitempty"`
-	BaseBranch         StringOrSliceRaw   `yaml:"base-branch,omitempty"`
-	HeadBranch         StringOrSliceRaw   `yaml:"head-branch,omitempty"`
-	Author             StringOrSliceRaw   `yaml:"author,omitempty"`
-	Color              string             `yaml:"color,omitempty"`
-	Description        string             `yaml:"description,omitempty"`
-	Codeowners         StringOrSlice      `yaml:"codeowners,omitempty"`
+	Any               []LabelerRule      `yaml:"any,omitempty"`
+	All               []LabelerRule      `yaml:"all,omitempty"`
+	Not               []LabelerRule      `yaml:"not,omitempty"`
+	None              []LabelerRule      `yaml:"none,omitempty"`
+	ChangedFiles      []ChangedFilesRule `yaml:"changed-files,omitempty"`
+	AllFilesToAnyGlob StringOrSlice      `yaml:"all-files-to-any-glob,omitempty"`
+	BaseBranch        StringOrSliceRaw   `yaml:"base-branch,omitempty"`
+	HeadBranch        StringOrSliceRaw   `yaml:"head-branch,omitempty"`
+	Author            StringOrSliceRaw   `yaml:"author,omitempty"`
+	Codeowner         StringOrSliceRaw   `yaml:"codeowner,omitempty"`
+	When              string             `yaml:"when,omitempty"`
+	Command           string             `yaml:"command,omitempty"`
+	Color             string             `yaml:"color,omitempty"`
+	Description       string             `yaml:"description,omitempty"`
+	Codeowners        StringOrSlice      `yaml:"codeowners,omitempty"`
+	RespectManual     bool               `yaml:"respect-manual,omitempty"`
+	IgnoreFiles       StringOrSlice      `yaml:"ignore-files,omitempty"`
 }
 
+// LabelerRule is a set of conditions. Inside "any" a rule matches if any of its conditions matches, and inside "all"
+// if all of them match. The any, all, not and none conditions nest rules: "not" matches unless all of its rules match,
+// and "none" matches if none of its rules matches.
 type LabelerRule struct {
+	Any               []LabelerRule      `yaml:"any,omitempty"`
+	All               []LabelerRule      `yaml:"all,omitempty"`
+	Not               []LabelerRule      `yaml:"not,omitempty"`
+	None              []LabelerRule      `yaml:"none,omitempty"`
 	ChangedFiles      []ChangedFilesRule `yaml:"changed-files,omitempty"`
 	AllFilesToAnyGlob StringOrSlice      `yaml:"all-files-to-any-glob,omitempty"`
 	BaseBranch        StringOrSliceRaw   `yaml:"base-branch,omitempty"`
 	HeadBranch        StringOrSliceRaw   `yaml:"head-branch,omitempty"`
 	Author            StringOrSliceRaw   `yaml:"author,omitempty"`
+	Codeowner         StringOrSliceRaw   `yaml:"codeowner,omitempty"`
+	When              string             `yaml:"when,omitempty"`
+	Command           string             `yaml:"command,omitempty"`
 }
 
 type ChangedFilesRule struct {
@@ -118,6 +139,9 @@ func (m *labelerYamlMatch) GetHeadBranch() []string {
 func (m *labelerYamlMatch) GetAuthor() []string {
 	return flattenStringOrSliceRaw(m.Author)
 }
+func (m *labelerYamlMatch) GetCodeowner() []string {
+	return flattenStringOrSliceRaw(m.Codeowner)
+}
 func (r *LabelerRule) GetBaseBranch() []string {
 	return flattenStringOrSliceRaw(r.BaseBranch)
 }
@@ -127,6 +151,9 @@ func (r *LabelerRule) GetHeadBranch() []string {
 func (r *LabelerRule) GetAuthor() []string {
 	return flattenStringOrSliceRaw(r.Author)
 }
+func (r *LabelerRule) GetCodeowner() []string {
+	return flattenStringOrSliceRaw(r.Codeowner)
+}
 
 // ColorOfLabel returns the color string for a label (if any), allowing for color-only elements in the config.
 func colorOfLabel(matches []labelerYamlMatch) string {
@@ -157,6 +184,27 @@ func codeownersOfLabel(matches []labelerYamlMatch) []string {
 	return slices.Collect(maps.Keys(ownerSet))
 }
 
+func respectManualOfLabel(matches []labelerYamlMatch) bool {
+	for _, m := range matches {
+		if m.RespectManual {
+			return true
+		}
+	}
+	return false
+}
+
+func ignoreFilesOfLabel(matches []labelerYamlMatch) []string {
+	var globs []string
+	for _, m := range matches {
+		for _, g := range m.IgnoreFiles {
+			if !slices.Contains(globs, g) {
+				globs = append(globs, g)
+			}
+		}
+	}
+	return globs
+}
+
 func (r *labelerYamlConfig) GetConfig() LabelerConfig {
 	cfg := make(LabelerConfig, len(*r))
 	for label, matches := range *r {
@@ -171,10 +219,12 @@ func (r *labelerYamlConfig) GetConfig() LabelerConfig {
 			}
 		}
 		cfg[label] = LabelerLabelConfig{
-			Matcher:     matchers,
-			Color:       colorOfLabel(matches),
-			Description: descriptionOfLabel(matches),
-			Codeowners:  codeownersOfLabel(matches),
+			Matcher:       matchers,
+			Color:         colorOfLabel(matches),
+			Description:   descriptionOfLabel(matches),
+			Codeowners:    codeownersOfLabel(matches),
+			RespectManual: respectManualOfLabel(matches),
+			IgnoreFiles:   ignoreFilesOfLabel(matches),
 		}
 	}
 	return cfg
@@ -188,6 +238,11 @@ func (r *LabelerRule) Normalize() {
 		})
 		r.AllFilesToAnyGlob = nil // Clear to avoid duplication
 	}
+	for _, nested := range [][]LabelerRule{r.Any, r.All, r.Not, r.None} {
+		for i := range nested {
+			nested[i].Normalize()
+		}
+	}
 }
 
 func (m *labelerYamlMatch) Normalize() {
@@ -212,11 +267,33 @@ func (m *labelerYamlMatch) Normalize() {
 		anyRules = append(anyRules, LabelerRule{Author: m.GetAuthor()})
 		m.Author = nil // Clear to avoid duplication
 	}
+	if m.Codeowner != nil {
+		anyRules = append(anyRules, LabelerRule{Codeowner: m.GetCodeowner()})
+		m.Codeowner = nil // Clear to avoid duplication
+	}
+	if m.When != "" {
+		anyRules = append(anyRules, LabelerRule{When: m.When})
+		m.When = "" // Clear to avoid duplication
+	}
+	if m.Command != "" {
+		anyRules = append(anyRules, LabelerRule{Command: m.Command})
+		m.Command = "" // Clear to avoid duplication
+	}
 	if len(m.ChangedFiles) > 0 {
 		anyRules = append(anyRules, LabelerRule{ChangedFiles: m.ChangedFiles})
 		m.ChangedFiles = nil // Clear to avoid duplication
 	}
 
+	// Negations must hold together with the other conditions of the element, so they are integrated into all
+	if len(m.Not) > 0 {
+		m.All = append(m.All, LabelerRule{Not: m.Not})
+		m.Not = nil // Clear to avoid duplication
+	}
+	if len(m.None) > 0 {
+		m.All = append(m.All, LabelerRule{None: m.None})
+		m.None = nil // Clear to avoid duplication
+	}
+
 	if len(anyRules) > 0 {
 		if m.Any == nil {
 			m.Any = anyRules
@@ -232,3 +309,22 @@ func (m *labelerYamlMatch) Normalize() {
 		m.All[i].Normalize()
 	}
 }
+
+// usesPullRequestDetails reports whether any rule has a when expression or a command, which may use the details of the PR
+// such as its line counts.
+func (c LabelerConfig) usesPullRequestDetails() bool {
+	var uses func(rules []LabelerRule) bool
+	uses = func(rules []LabelerRule) bool {
+		return slices.ContainsFunc(rules, func(r LabelerRule) bool {
+			return r.When != "" || r.Command != "" || uses(slices.Concat(r.Any, r.All, r.Not, r.None))
+		})
+	}
+	for _, labelConfig := range c {
+		for _, m := range labelConfig.Matcher {
+			if uses(slices.Concat(m.Any, m.All)) {
+				return true
+			}
+		}
+	}
+	return false
+}
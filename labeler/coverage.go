@@ -0,0 +1,176 @@
+package labeler
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// DefaultMinOverlap is the default ratio of shared files above which two labels are reported as overlapping.
+const DefaultMinOverlap = 0.8
+
+// globsOfRule returns all globs of the changed-files rule regardless of how they are combined.
+func globsOfRule(r ChangedFilesRule) []string {
+	var globs []string
+	globs = append(globs, r.AnyGlobToAnyFile...)
+	globs = append(globs, r.AnyGlobToAllFiles...)
+	globs = append(globs, r.AllGlobsToAnyFile...)
+	globs = append(globs, r.AllGlobsToAllFiles...)
+	globs = append(globs, r.AllFilesToAnyGlob...)
+	return globs
+}
+
+// appendRuleGlobs appends the changed-files globs of the rules and their nested any and all rules, without duplicates.
+// Negated globs ("!pattern") and the rules under not and none are excluded since they match the files the label is not about.
+func appendRuleGlobs(globs []string, rules []LabelerRule) []string {
+	for _, rule := range rules {
+		for _, cf := range rule.ChangedFiles {
+			for _, g := range globsOfRule(cf) {
+				if strings.HasPrefix(g, "!") && !containsExtglob(g) {
+					continue
+				}
+				if !slices.Contains(globs, g) {
+					globs = append(globs, g)
+				}
+			}
+		}
+		globs = appendRuleGlobs(globs, slices.Concat(rule.Any, rule.All))
+	}
+	return globs
+}
+
+// labelGlobs returns the changed-files globs of each label, without duplicates.
+func labelGlobs(cfg LabelerConfig) map[string][]string {
+	result := make(map[string][]string)
+	for name, lc := range cfg {
+		var globs []string
+		for _, m := range lc.Matcher {
+			globs = appendRuleGlobs(globs, slices.Concat(m.Any, m.All))
+		}
+		if len(globs) > 0 {
+			result[name] = globs
+		}
+	}
+	return result
+}
+
+// ComputeCoverage reports the files that no label's changed-files globs match, the globs that match no file,
+// and the pairs of labels that share at least minOverlap of the files of the smaller one.
+// A file is matched by a label if any of its globs matches, regardless of how the globs are combined in the rule.
+// Hidden files are skipped if the no-hidden option is enabled.
+func ComputeCoverage(cfg LabelerConfig, files []string, minOverlap float64) *labels.CoverageReport {
+	if isNoHiddenEnabled() {
+		files = slices.DeleteFunc(slices.Clone(files), isHiddenFile)
+	}
+	globs := labelGlobs(cfg)
+	names := slices.Sorted(maps.Keys(globs))
+	matchedBy := make(map[string]map[string]struct{}, len(names))
+	report := &labels.CoverageReport{
+		Files:     len(files),
+		Unmatched: []string{},
+		DeadGlobs: []labels.DeadGlob{},
+		Overlaps:  []labels.LabelOverlap{},
+	}
+	for _, name := range names {
+		matchedBy[name] = make(map[string]struct{})
+		for _, pattern := range globs[name] {
+			hit := false
+			for _, f := range files {
+				if matchGlob(pattern, f) {
+					matchedBy[name][f] = struct{}{}
+					hit = true
+				}
+			}
+			if !hit {
+				report.DeadGlobs = append(report.DeadGlobs, labels.DeadGlob{Label: name, Pattern: pattern})
+			}
+		}
+	}
+
+	unmatched := make(map[string]struct{})
+	for _, f := range files {
+		covered := slices.ContainsFunc(names, func(name string) bool {
+			_, ok := matchedBy[name][f]
+			return ok
+		})
+		if !covered {
+			unmatched[f] = struct{}{}
+		}
+	}
+	report.Matched = len(files) - len(unmatched)
+	report.Unmatched = labels.CollapseUnmatched(files, unmatched)
+
+	for i, a := range names {
+		for _, b := range names[i+1:] {
+			smaller := min(len(matchedBy[a]), len(matchedBy[b]))
+			if smaller == 0 {
+				continue
+			}
+			shared := 0
+			for f := range matchedBy[a] {
+				if _, ok := matchedBy[b][f]; ok {
+					shared++
+				}
+			}
+			ratio := float64(shared) / float64(smaller)
+			if shared > 0 && ratio >= minOverlap {
+				report.Overlaps = append(report.Overlaps, labels.LabelOverlap{Labels: []string{a, b}, Shared: shared, Ratio: ratio})
+			}
+		}
+	}
+	logger.Debug("Computed coverage", "files", report.Files, "matched", report.Matched, "deadGlobs", len(report.DeadGlobs), "overlaps", len(report.Overlaps))
+	return report
+}
+
+// ListLocalFiles lists the files under the directory as slash-separated paths relative to it, skipping the .git directory.
+func ListLocalFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// ListRepositoryFiles lists the files of the repository tree at the ref.
+func ListRepositoryFiles(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, ref string) ([]string, error) {
+	tree, _, err := g.GetClient().Git.GetTree(ctx, repo.Owner, repo.Name, ref, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of %s: %w", ref, err)
+	}
+	if tree.GetTruncated() {
+		logger.Warn("Repository tree is truncated, some files are not checked", "ref", ref)
+	}
+	var files []string
+	for _, e := range tree.Entries {
+		if e.GetType() == "blob" {
+			files = append(files, e.GetPath())
+		}
+	}
+	return files, nil
+}
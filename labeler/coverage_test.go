@@ -0,0 +1,45 @@
+package labeler
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestComputeCoverage(t *testing.T) {
+	yamlContent := `
+backend:
+  - changed-files:
+    - any-glob-to-any-file:
+      - 'api/**'
+      - 'server/**'
+      - '!api/legacy/**'
+go:
+  - all-files-to-any-glob: '**/*.go'
+frontend:
+  - any:
+    - changed-files:
+      - any-glob-to-any-file: 'web/**'
+docs:
+  - head-branch: '^docs/'
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(yamlContent), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	files := []string{"README.md", "api/main.go", "api/handler.go", "tools/gen/main.go", "tools/lint.sh", "web/app.ts"}
+	report := ComputeCoverage(cfg, files, DefaultMinOverlap)
+
+	if report.Files != 6 || report.Matched != 4 {
+		t.Errorf("unexpected counts: files=%d matched=%d", report.Files, report.Matched)
+	}
+	if !slices.Equal(report.Unmatched, []string{"README.md", "tools/lint.sh"}) {
+		t.Errorf("unexpected unmatched: %v", report.Unmatched)
+	}
+	if len(report.DeadGlobs) != 1 || report.DeadGlobs[0].Label != "backend" || report.DeadGlobs[0].Pattern != "server/**" {
+		t.Errorf("unexpected dead globs: %+v", report.DeadGlobs)
+	}
+	if len(report.Overlaps) != 1 || !slices.Equal(report.Overlaps[0].Labels, []string{"backend", "go"}) || report.Overlaps[0].Shared != 2 || report.Overlaps[0].Ratio != 1 {
+		t.Errorf("unexpected overlaps: %+v", report.Overlaps)
+	}
+}
@@ -2,32 +2,32 @@ package labeler
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/gh-label-kit/labels"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 	"github.com/srz-zumix/go-gh-extension/pkg/logger"
 )
 
 // EditLabelsByConfig edits the given labels according to the config (color, etc). Returns the edited labels.
-func EditLabelsByConfig(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, labels []*Label, config LabelerConfig) ([]*Label, error) {
-	logger.Debug("Editing labels by config", "labelsCount", len(labels))
+func EditLabelsByConfig(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, repoLabels []*Label, config LabelerConfig) ([]*Label, error) {
+	logger.Debug("Editing labels by config", "labelsCount", len(repoLabels))
 	labelMap := make(map[string]*Label)
 	var edited []*Label
-	for _, l := range labels {
+	for _, l := range repoLabels {
 		if l == nil || l.Name == nil {
 			continue
 		}
 		labelMap[*l.Name] = l
 	}
 	for name, cfg := range config {
-		color := cfg.Color
+		color := labels.NormalizeColor(cfg.Color)
 		description := cfg.Description
 		if color == "" && description == "" {
 			continue
 		}
-		if color != "" && color[0] == '#' {
-			color = color[1:]
-		}
 		if l, ok := labelMap[name]; ok {
 			needsUpdate := false
 			if color != "" && (l.Color == nil || *l.Color != color) {
@@ -52,3 +52,86 @@ func EditLabelsByConfig(ctx context.Context, g *gh.GitHubClient, repo repository
 	logger.Debug("Finished editing labels", "editedCount", len(edited))
 	return edited, nil
 }
+
+// undefinedLabels returns the names that are not found in the defined labels.
+// Label names are compared case-insensitively, as GitHub does.
+func undefinedLabels(names []string, defined []*Label) []string {
+	definedSet := make(map[string]struct{}, len(defined))
+	for _, l := range defined {
+		definedSet[strings.ToLower(l.GetName())] = struct{}{}
+	}
+	var undefined []string
+	for _, name := range names {
+		if _, ok := definedSet[strings.ToLower(name)]; !ok {
+			undefined = append(undefined, name)
+		}
+	}
+	return undefined
+}
+
+// FindUndefinedLabels returns the label names that do not exist in the repository.
+func FindUndefinedLabels(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	defined, err := gh.ListLabels(ctx, g, repo)
+	if err != nil {
+		logger.Debug("Failed to list repository labels", "error", err)
+		return nil, err
+	}
+	undefined := undefinedLabels(names, defined)
+	logger.Debug("Found undefined labels", "labels", undefined)
+	return undefined, nil
+}
+
+// newLabelByConfig builds a label with the color and description from the config.
+// Empty values are left unset so that GitHub applies its defaults.
+func newLabelByConfig(name string, config LabelerConfig) *Label {
+	label := &Label{Name: Ptr(name)}
+	if cfg, ok := config[name]; ok {
+		if color := labels.NormalizeColor(cfg.Color); color != "" {
+			label.Color = Ptr(color)
+		}
+		if cfg.Description != "" {
+			label.Description = Ptr(cfg.Description)
+		}
+	}
+	return label
+}
+
+// CreateLabelsByConfig creates the given labels in the repository with the color and description from the config.
+func CreateLabelsByConfig(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, names []string, config LabelerConfig) ([]*Label, error) {
+	logger.Debug("Creating labels by config", "labels", names)
+	var created []*Label
+	for _, name := range names {
+		l := newLabelByConfig(name, config)
+		result, err := gh.CreateLabel(ctx, g, repo, l.Name, l.Description, l.Color)
+		if err != nil {
+			logger.Debug("Failed to create label", "name", name, "error", err)
+			return nil, err
+		}
+		created = append(created, result)
+	}
+	logger.Debug("Finished creating labels", "createdCount", len(created))
+	return created, nil
+}
+
+// Definitions returns the label definitions (color and description) of the config, sorted by name.
+// Labels without color and description in the config are included so that they are created if missing.
+func (c LabelerConfig) Definitions() []labels.Definition {
+	definitions := make([]labels.Definition, 0, len(c))
+	for name, cfg := range c {
+		d := labels.Definition{Name: name}
+		if color := labels.NormalizeColor(cfg.Color); color != "" {
+			d.Color = Ptr(color)
+		}
+		if cfg.Description != "" {
+			d.Description = Ptr(cfg.Description)
+		}
+		definitions = append(definitions, d)
+	}
+	slices.SortFunc(definitions, func(a, b labels.Definition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return definitions
+}
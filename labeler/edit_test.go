@@ -0,0 +1,72 @@
+package labeler
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUndefinedLabels(t *testing.T) {
+	defined := []*Label{
+		{Name: Ptr("bug")},
+		{Name: Ptr("Documentation")},
+	}
+	got := undefinedLabels([]string{"bug", "documentation", "enhancment"}, defined)
+	want := []string{"enhancment"}
+	if !slices.Equal(got, want) {
+		t.Errorf("undefinedLabels() = %v, want %v", got, want)
+	}
+	if got := undefinedLabels([]string{"bug"}, defined); len(got) != 0 {
+		t.Errorf("undefinedLabels() = %v, want empty", got)
+	}
+}
+
+func TestNewLabelByConfig(t *testing.T) {
+	cfg := LabelerConfig{
+		"bug": LabelerLabelConfig{
+			Color:       "#d73a4a",
+			Description: "Something isn't working",
+		},
+		"no-metadata": LabelerLabelConfig{},
+	}
+
+	l := newLabelByConfig("bug", cfg)
+	if l.GetName() != "bug" {
+		t.Errorf("name = %q, want %q", l.GetName(), "bug")
+	}
+	if l.Color == nil || *l.Color != "d73a4a" {
+		t.Errorf("color = %v, want %q", l.Color, "d73a4a")
+	}
+	if l.Description == nil || *l.Description != "Something isn't working" {
+		t.Errorf("description = %v, want %q", l.Description, "Something isn't working")
+	}
+
+	l = newLabelByConfig("no-metadata", cfg)
+	if l.Color != nil || l.Description != nil {
+		t.Errorf("color and description should be unset, got color=%v description=%v", l.Color, l.Description)
+	}
+
+	l = newLabelByConfig("unknown", cfg)
+	if l.GetName() != "unknown" || l.Color != nil || l.Description != nil {
+		t.Errorf("unexpected label for unknown config: %+v", l)
+	}
+}
+
+func TestLabelerConfig_Definitions(t *testing.T) {
+	cfg := LabelerConfig{
+		"bug":      LabelerLabelConfig{Color: "#d73a4a", Description: "Something isn't working"},
+		"analysis": LabelerLabelConfig{},
+	}
+	definitions := cfg.Definitions()
+	if len(definitions) != 2 {
+		t.Fatalf("expected 2 definitions, got %d", len(definitions))
+	}
+	if definitions[0].Name != "analysis" || definitions[1].Name != "bug" {
+		t.Errorf("definitions should be sorted by name, got %s, %s", definitions[0].Name, definitions[1].Name)
+	}
+	if definitions[0].Color != nil || definitions[0].Description != nil {
+		t.Errorf("analysis should not manage color and description, got %+v", definitions[0])
+	}
+	if definitions[1].GetColor() != "d73a4a" || definitions[1].GetDescription() != "Something isn't working" {
+		t.Errorf("unexpected bug definition: %+v", definitions[1])
+	}
+}
@@ -2,14 +2,18 @@ package labeler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/cli/go-gh/v2/pkg/repository"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 	"github.com/srz-zumix/go-gh-extension/pkg/logger"
 )
 
-func SetLabels(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, pr *PullRequest, allLabels []string, cfg LabelerConfig) ([]*Label, error) {
+// SetLabels sets the labels of the PR. Labels that do not exist in the repository are created
+// with the color and description from the config, or skipped and reported as an error if noCreate is true.
+func SetLabels(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, pr *PullRequest, allLabels []string, cfg LabelerConfig, noCreate bool) ([]*Label, error) {
 	logger.Debug("Setting labels for PR", "pr", pr.GetNumber(), "labels", allLabels, "count", len(allLabels))
 	var excessLabels []string
 	if len(allLabels) > 100 {
@@ -17,6 +21,23 @@ func SetLabels(ctx context.Context, g *gh.GitHubClient, repo repository.Reposito
 		allLabels = allLabels[:100]
 		logger.Debug("Label count exceeds limit, truncating", "pr", pr.GetNumber(), "limit", 100, "excess", excessLabels)
 	}
+	undefined, err := FindUndefinedLabels(ctx, g, repo, allLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check labels for PR #%d: %w", pr.GetNumber(), err)
+	}
+	if len(undefined) > 0 {
+		if noCreate {
+			logger.Debug("Skipping labels not defined in repository", "pr", pr.GetNumber(), "labels", undefined)
+			allLabels = slices.DeleteFunc(slices.Clone(allLabels), func(name string) bool {
+				return slices.Contains(undefined, name)
+			})
+		} else {
+			_, err = CreateLabelsByConfig(ctx, g, repo, undefined, cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create labels for PR #%d: %w", pr.GetNumber(), err)
+			}
+		}
+	}
 	labels, err := gh.SetPullRequestLabels(ctx, g, repo, pr, allLabels)
 	if err != nil {
 		logger.Debug("Failed to set PR labels", "pr", pr.GetNumber(), "error", err)
@@ -26,8 +47,15 @@ func SetLabels(ctx context.Context, g *gh.GitHubClient, repo repository.Reposito
 	if err != nil {
 		return nil, fmt.Errorf("failed to edit labels for PR #%d: %w", pr.GetNumber(), err)
 	}
+	var errs []error
+	if noCreate && len(undefined) > 0 {
+		errs = append(errs, fmt.Errorf("labels not defined in repository: not applied to PR #%d: %v", pr.GetNumber(), undefined))
+	}
 	if len(excessLabels) > 0 {
-		return labels, fmt.Errorf("label limit for a PR exceeded: not applied to PR #%d: %v", pr.GetNumber(), excessLabels)
+		errs = append(errs, fmt.Errorf("label limit for a PR exceeded: not applied to PR #%d: %v", pr.GetNumber(), excessLabels))
+	}
+	if len(errs) > 0 {
+		return labels, errors.Join(errs...)
 	}
 	logger.Debug("Successfully set labels for PR", "pr", pr.GetNumber(), "labels", len(labels))
 	return labels, nil
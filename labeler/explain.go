@@ -0,0 +1,64 @@
+package labeler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/srz-zumix/gh-label-kit/labels"
+)
+
+// tracer records the evaluation of the conditions of a label for --explain. A nil tracer records nothing.
+type tracer struct {
+	node *labels.MatchTrace
+}
+
+func newTracer(name string) *tracer {
+	return &tracer{node: &labels.MatchTrace{Name: name}}
+}
+
+// child adds a condition under the traced one and returns its tracer.
+func (t *tracer) child(name string) *tracer {
+	if t == nil {
+		return nil
+	}
+	c := &labels.MatchTrace{Name: name}
+	t.node.Children = append(t.node.Children, c)
+	return &tracer{node: c}
+}
+
+// done records the result of the traced condition and returns it.
+func (t *tracer) done(state matchState) matchState {
+	if t != nil {
+		t.node.Matched = state == stateMatched
+		t.node.Failed = state == stateFailed
+	}
+	return state
+}
+
+// leaf records a single condition with its values and result, and returns the result.
+func (t *tracer) leaf(name string, values []string, state matchState) matchState {
+	if t == nil {
+		return state
+	}
+	return t.child(fmt.Sprintf("%s: %s", name, strings.Join(values, ", "))).done(state)
+}
+
+// describeChangedFilesRule returns the globs of each option of the changed-files rule.
+func describeChangedFilesRule(cf ChangedFilesRule) []string {
+	var parts []string
+	for _, o := range []struct {
+		name  string
+		globs []string
+	}{
+		{"any-glob-to-any-file", cf.AnyGlobToAnyFile},
+		{"any-glob-to-all-files", cf.AnyGlobToAllFiles},
+		{"all-globs-to-any-file", cf.AllGlobsToAnyFile},
+		{"all-globs-to-all-files", cf.AllGlobsToAllFiles},
+		{"all-files-to-any-glob", cf.AllFilesToAnyGlob},
+	} {
+		if len(o.globs) > 0 {
+			parts = append(parts, fmt.Sprintf("%s [%s]", o.name, strings.Join(o.globs, ", ")))
+		}
+	}
+	return parts
+}
@@ -0,0 +1,124 @@
+package labeler
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/srz-zumix/gh-label-kit/labels"
+)
+
+// backend AND NOT (docs-only OR bot author)
+const nestedConfig = `
+backend:
+  - all:
+    - changed-files:
+      - any-glob-to-any-file: 'api/**'
+    - none:
+      - all-files-to-any-glob: ['api/**/*.md', 'docs/**']
+      - author: '.*\[bot\]$'
+release:
+  - base-branch: 'main'
+  - not:
+    - head-branch: '^wip/'
+    - any:
+      - changed-files:
+        - any-glob-to-any-file: 'docs/**'
+      - author: 'octocat'
+`
+
+func nestedPullRequest(author, head string) *PullRequest {
+	return &PullRequest{
+		Base:   &PullRequestBranch{Ref: Ptr("main")},
+		Head:   &PullRequestBranch{Ref: Ptr(head)},
+		User:   &User{Login: Ptr(author)},
+		Labels: []*Label{},
+	}
+}
+
+func TestCheckMatchConfigs_Nested(t *testing.T) {
+	cfg, err := LoadConfigFromReader(strings.NewReader(nestedConfig), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	tests := []struct {
+		name   string
+		author string
+		head   string
+		files  []string
+		want   []string
+	}{
+		{name: "code by human", author: "alice", head: "feature", files: []string{"api/server.go"}, want: []string{"backend", "release"}},
+		{name: "docs only", author: "alice", head: "feature", files: []string{"api/README.md", "docs/index.md"}, want: []string{"release"}},
+		{name: "bot author", author: "renovate[bot]", head: "feature", files: []string{"api/server.go"}, want: []string{"release"}},
+		{name: "wip docs by octocat", author: "octocat", head: "wip/docs", files: []string{"api/server.go", "docs/index.md"}, want: []string{"backend"}},
+		{name: "wip code", author: "alice", head: "wip/x", files: []string{"api/server.go"}, want: []string{"backend", "release"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []*CommitFile
+			for _, f := range tt.files {
+				files = append(files, &CommitFile{Filename: Ptr(f)})
+			}
+			result := NewMatcher(context.TODO(), nil).CheckMatchConfigs(cfg, files, nestedPullRequest(tt.author, tt.head))
+			if !slices.Equal(result.Matched, tt.want) {
+				t.Errorf("matched = %v, want %v", result.Matched, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_NestedUnknownFieldStrict(t *testing.T) {
+	content := `
+backend:
+  - all:
+    - not:
+      - head-brnch: 'wip'
+`
+	if _, err := LoadConfigFromReader(strings.NewReader(content), true); err == nil {
+		t.Errorf("expected an error for an unknown field in a nested rule")
+	}
+}
+
+func TestExplainMatchConfigs(t *testing.T) {
+	cfg, err := LoadConfigFromReader(strings.NewReader(nestedConfig), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	files := []*CommitFile{{Filename: Ptr("api/server.go")}, {Filename: Ptr("docs/index.md")}}
+	result, traces := NewMatcher(context.TODO(), nil).ExplainMatchConfigs(cfg, files, nestedPullRequest("octocat", "wip/docs"))
+	if !slices.Equal(result.Matched, []string{"backend"}) {
+		t.Fatalf("matched = %v", result.Matched)
+	}
+	if len(traces) != 2 || traces[0].Name != "backend" || !traces[0].Matched || traces[1].Name != "release" || traces[1].Matched {
+		t.Fatalf("unexpected traces: %+v", traces)
+	}
+
+	var lines []string
+	var walk func(tr *labels.MatchTrace, depth int)
+	walk = func(tr *labels.MatchTrace, depth int) {
+		mark := "-"
+		if tr.Matched {
+			mark = "+"
+		}
+		lines = append(lines, strings.Repeat("  ", depth)+mark+" "+tr.Name)
+		for _, c := range tr.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(traces[1], 0)
+	want := []string{
+		"- release",
+		"  + any",
+		"    + base-branch: main",
+		"  - all",
+		"    - not",
+		"      + head-branch: ^wip/",
+		"      + any",
+		"        + changed-files: any-glob-to-any-file [docs/**]",
+	}
+	if !slices.Equal(lines, want) {
+		t.Errorf("trace =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,140 @@
+package labeler
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// ignoreKey is the top-level key of the config listing the globs of changed files to ignore for every label.
+const ignoreKey = "ignore"
+
+// labelerYamlDocument is a config with a top-level ignore list. The list is skipped when the labels are decoded, so that
+// the document is decoded as written and anchors defined on the list stay available to the labels.
+type labelerYamlDocument struct {
+	Ignore yaml.Node         `yaml:"ignore"`
+	Labels labelerYamlConfig `yaml:",inline"`
+}
+
+// findIgnore returns the globs of the top-level ignore list of the document.
+// The key is only treated as the ignore list if its value is a glob or a list of globs, so that a label named "ignore"
+// (whose value is a list of match objects) keeps working.
+func findIgnore(doc *yaml.Node) ([]string, bool, error) {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, false, nil
+	}
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != ignoreKey {
+			continue
+		}
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		if value.Kind == yaml.SequenceNode && slices.ContainsFunc(value.Content, func(n *yaml.Node) bool { return n.Kind == yaml.MappingNode }) {
+			return nil, false, nil
+		}
+		var globs StringOrSlice
+		if err := value.Decode(&globs); err != nil {
+			return nil, false, fmt.Errorf("invalid %s: %w", ignoreKey, err)
+		}
+		return globs, true, nil
+	}
+	return nil, false, nil
+}
+
+// decodeLabels decodes the labels of the config, skipping the top-level ignore list if hasIgnore is true.
+// If knownFields is true, unknown fields of the labels are errors.
+func decodeLabels(data []byte, hasIgnore bool, knownFields bool) (labelerYamlConfig, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(knownFields)
+	if !hasIgnore {
+		var cfg labelerYamlConfig
+		err := decoder.Decode(&cfg)
+		return cfg, err
+	}
+	var doc labelerYamlDocument
+	err := decoder.Decode(&doc)
+	return doc.Labels, err
+}
+
+// AddIgnoreFiles adds the globs to the changed files ignored by every label.
+func (c LabelerConfig) AddIgnoreFiles(globs []string) {
+	if len(globs) == 0 {
+		return
+	}
+	for name, lc := range c {
+		for _, g := range globs {
+			if !slices.Contains(lc.IgnoreFiles, g) {
+				lc.IgnoreFiles = append(lc.IgnoreFiles, g)
+			}
+		}
+		c[name] = lc
+	}
+}
+
+// filterIgnoredFiles returns the changed files that match none of the ignore globs.
+func filterIgnoredFiles(globs []string, changedFiles []*CommitFile) []*CommitFile {
+	if len(globs) == 0 {
+		return changedFiles
+	}
+	return slices.DeleteFunc(slices.Clone(changedFiles), func(f *CommitFile) bool {
+		for _, g := range globs {
+			if matchGlob(g, f.GetFilename()) {
+				logger.Debug("Ignoring changed file", "file", f.GetFilename(), "pattern", g)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// gitPatternGlob converts a gitignore-style pattern, as used by CODEOWNERS and .gitattributes, to a doublestar glob.
+// A pattern without a slash (other than a trailing one) matches at any depth, and a leading slash anchors it to the root.
+func gitPatternGlob(pattern string) string {
+	glob := strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(glob, "/") {
+		return "**/" + glob
+	}
+	return strings.TrimPrefix(glob, "/")
+}
+
+// LoadGeneratedGlobs returns the globs of the files marked as linguist-generated in the .gitattributes file of the
+// repository at root. It returns nil without error if there is no .gitattributes file.
+func LoadGeneratedGlobs(root string) ([]string, error) {
+	f, err := os.Open(filepath.Join(root, ".gitattributes"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open .gitattributes: %w", err)
+	}
+	defer f.Close() // nolint
+	var globs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if !slices.Contains(fields[1:], "linguist-generated") && !slices.Contains(fields[1:], "linguist-generated=true") {
+			continue
+		}
+		glob := gitPatternGlob(fields[0])
+		globs = append(globs, glob, glob+"/**")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	logger.Debug("Generated file globs loaded from .gitattributes", "globs", globs)
+	return globs, nil
+}
@@ -0,0 +1,137 @@
+package labeler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig_Ignore(t *testing.T) {
+	yamlContent := `
+ignore:
+  - go.sum
+  - 'vendor/**'
+documentation:
+  - all-files-to-any-glob: 'docs/**'
+api:
+  - changed-files:
+    - any-glob-to-any-file: 'api/**'
+  - ignore-files: 'api/**/*.pb.go'
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(yamlContent), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	if len(cfg) != 2 {
+		t.Fatalf("expected 2 labels, got %v", cfg)
+	}
+	if got := cfg["documentation"].IgnoreFiles; !slices.Equal(got, []string{"go.sum", "vendor/**"}) {
+		t.Errorf("documentation ignore = %v", got)
+	}
+	if got := cfg["api"].IgnoreFiles; !slices.Equal(got, []string{"api/**/*.pb.go", "go.sum", "vendor/**"}) {
+		t.Errorf("api ignore = %v", got)
+	}
+}
+
+func TestLoadConfig_IgnoreAnchor(t *testing.T) {
+	// The ignore list is decoded in place, so its anchor can be used by labels
+	yamlContent := `
+ignore: &generated
+  - go.sum
+documentation:
+  - all-files-to-any-glob: 'docs/**'
+  - ignore-files: *generated
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(yamlContent), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	if got := cfg["documentation"].IgnoreFiles; !slices.Equal(got, []string{"go.sum"}) {
+		t.Errorf("documentation ignore = %v", got)
+	}
+
+	// Unknown fields are reported at their line in the document
+	_, err = LoadConfigFromReader(strings.NewReader("ignore: go.sum\ndocumentation:\n  - unknown: x\n"), true)
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("expected an unknown field error at line 3, got %v", err)
+	}
+}
+
+func TestLoadConfig_LabelNamedIgnore(t *testing.T) {
+	yamlContent := `
+ignore:
+  - changed-files:
+    - any-glob-to-any-file: '.gitignore'
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(yamlContent), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	lc, ok := cfg["ignore"]
+	if !ok || len(lc.Matcher) != 1 || len(lc.IgnoreFiles) != 0 {
+		t.Errorf("label named ignore should be loaded as a label: %+v", cfg)
+	}
+}
+
+func TestCheckMatchConfigs_IgnoreFiles(t *testing.T) {
+	yamlContent := `
+ignore: go.sum
+documentation:
+  - all-files-to-any-glob: 'docs/**'
+api:
+  - changed-files:
+    - any-glob-to-any-file: 'api/**'
+  - ignore-files: 'api/**/*.pb.go'
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(yamlContent), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	pr := &PullRequest{
+		Base:   &PullRequestBranch{Ref: Ptr("main")},
+		Head:   &PullRequestBranch{Ref: Ptr("feature")},
+		Labels: []*Label{},
+	}
+	files := []*CommitFile{{Filename: Ptr("go.sum")}, {Filename: Ptr("docs/index.md")}, {Filename: Ptr("api/v1/api.pb.go")}}
+	result := NewMatcher(context.TODO(), nil).CheckMatchConfigs(cfg, files, pr)
+	if !slices.Equal(result.Matched, []string{}) {
+		t.Errorf("matched = %v, want none", result.Matched)
+	}
+
+	files = files[:2]
+	result = NewMatcher(context.TODO(), nil).CheckMatchConfigs(cfg, files, pr)
+	if !slices.Equal(result.Matched, []string{"documentation"}) {
+		t.Errorf("matched = %v, want [documentation]", result.Matched)
+	}
+	if len(files) != 2 || files[0].GetFilename() != "go.sum" {
+		t.Errorf("changed files should not be modified: %v", files)
+	}
+}
+
+func TestLoadGeneratedGlobs(t *testing.T) {
+	dir := t.TempDir()
+	globs, err := LoadGeneratedGlobs(dir)
+	if err != nil || globs != nil {
+		t.Fatalf("expected nil without .gitattributes, got %v, %v", globs, err)
+	}
+	content := `# Generated code
+*.pb.go linguist-generated
+/gen/ linguist-generated=true
+docs/** linguist-documentation
+vendor/** -linguist-generated
+`
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	globs, err = LoadGeneratedGlobs(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"**/*.pb.go", "**/*.pb.go/**", "gen", "gen/**"}
+	if !slices.Equal(globs, want) {
+		t.Errorf("globs = %v, want %v", globs, want)
+	}
+}
@@ -0,0 +1,138 @@
+package labeler
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// DefaultAreaPrefix is the default prefix of the area labels proposed by labeler init.
+const DefaultAreaPrefix = "area/"
+
+// areaColors are the colors assigned to the proposed area labels in turn.
+var areaColors = []string{
+	"1d76db", "0e8a16", "fbca04", "d93f0b", "5319e7", "006b75",
+	"b60205", "c5def5", "bfd4f2", "f9d0c4", "d4c5f9", "c2e0c6",
+}
+
+// skippedAreaDirs are the directories that are never proposed as areas.
+var skippedAreaDirs = []string{"vendor", "node_modules", "testdata"}
+
+// Area is a part of the repository proposed as a label: a top-level directory or a Go module.
+type Area struct {
+	Label       string   `json:"label"`
+	Path        string   `json:"path"`
+	Module      bool     `json:"module"`
+	Files       int      `json:"files"`
+	Color       string   `json:"color"`
+	Description string   `json:"description"`
+	Codeowners  []string `json:"codeowners"`
+}
+
+// Glob returns the changed-files glob of the area.
+func (a Area) Glob() string {
+	return a.Path + "/**"
+}
+
+// skipAreaDir reports whether the directory or one of its parents is hidden or never proposed as an area.
+func skipAreaDir(dir string) bool {
+	for _, s := range strings.Split(dir, "/") {
+		if strings.HasPrefix(s, ".") || slices.Contains(skippedAreaDirs, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanAreas proposes an area for each top-level directory and each nested Go module (a directory with a go.mod file)
+// of the files, which are slash-separated paths relative to the repository root. Hidden directories, vendor, node_modules
+// and testdata are skipped. The areas are sorted by path and labeled with the prefix followed by the path.
+func ScanAreas(files []string, prefix string) []Area {
+	modules := make(map[string]struct{})
+	dirs := make(map[string]struct{})
+	for _, f := range files {
+		if top, _, ok := strings.Cut(f, "/"); ok && !skipAreaDir(top) {
+			dirs[top] = struct{}{}
+		}
+		if path.Base(f) == "go.mod" {
+			if dir := path.Dir(f); dir != "." && !skipAreaDir(dir) {
+				modules[dir] = struct{}{}
+				dirs[dir] = struct{}{}
+			}
+		}
+	}
+	var areas []Area
+	for _, dir := range slices.Sorted(maps.Keys(dirs)) {
+		_, module := modules[dir]
+		a := Area{
+			Label:  prefix + dir,
+			Path:   dir,
+			Module: module,
+			Color:  areaColors[len(areas)%len(areaColors)],
+		}
+		for _, f := range files {
+			if strings.HasPrefix(f, dir+"/") {
+				a.Files++
+			}
+		}
+		if module {
+			a.Description = fmt.Sprintf("Changes to the %s Go module", dir)
+		} else {
+			a.Description = fmt.Sprintf("Changes to %s", dir)
+		}
+		areas = append(areas, a)
+	}
+	return areas
+}
+
+// AssignCodeowners sets the codeowners of each area to the owners of the CODEOWNERS rule that owns the most files of the area.
+// The catch-all rule ("*") is ignored since its owners are not specific to any area.
+func AssignCodeowners(areas []Area, files []string, c *Codeowners) {
+	if c == nil {
+		return
+	}
+	for i := range areas {
+		counts := make(map[*CodeownersRule]int)
+		var best *CodeownersRule
+		for _, f := range files {
+			if !strings.HasPrefix(f, areas[i].Path+"/") {
+				continue
+			}
+			r := c.rule(f)
+			if r == nil || r.Pattern == "*" {
+				continue
+			}
+			counts[r]++
+			if best == nil || counts[r] > counts[best] {
+				best = r
+			}
+		}
+		if best != nil {
+			areas[i].Codeowners = slices.Clone(best.Owners)
+		}
+	}
+}
+
+// FormatConfig renders the areas as a labeler.yml with the color, description and codeowners of each label.
+func FormatConfig(areas []Area) string {
+	var b strings.Builder
+	for i, a := range areas {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s:\n", yamlKey(a.Label))
+		b.WriteString("  - changed-files:\n")
+		fmt.Fprintf(&b, "    - any-glob-to-any-file: %s\n", yamlQuote(a.Glob()))
+		fmt.Fprintf(&b, "  - color: %s\n", yamlQuote(a.Color))
+		fmt.Fprintf(&b, "  - description: %s\n", yamlQuote(a.Description))
+		if len(a.Codeowners) > 0 {
+			b.WriteString("  - codeowners:\n")
+			for _, o := range a.Codeowners {
+				fmt.Fprintf(&b, "    - %s\n", yamlQuote(o))
+			}
+		}
+	}
+	return b.String()
+}
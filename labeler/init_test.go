@@ -0,0 +1,82 @@
+package labeler
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+var testRepositoryFiles = []string{
+	".github/workflows/ci.yml",
+	"README.md",
+	"go.mod",
+	"api/server.go",
+	"api/handler/user.go",
+	"docs/index.md",
+	"tools/gen/go.mod",
+	"tools/gen/main.go",
+	"vendor/example.com/lib/lib.go",
+	"web/node_modules/pkg/index.js",
+	"web/app.ts",
+}
+
+func TestScanAreas(t *testing.T) {
+	areas := ScanAreas(testRepositoryFiles, "area/")
+	var paths []string
+	for _, a := range areas {
+		paths = append(paths, a.Path)
+	}
+	if want := []string{"api", "docs", "tools", "tools/gen", "web"}; !slices.Equal(paths, want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	gen := areas[3]
+	if gen.Label != "area/tools/gen" || !gen.Module || gen.Files != 2 || gen.Glob() != "tools/gen/**" {
+		t.Errorf("unexpected module area: %+v", gen)
+	}
+	if areas[0].Module || areas[0].Files != 2 {
+		t.Errorf("unexpected directory area: %+v", areas[0])
+	}
+	if areas[0].Color == areas[1].Color {
+		t.Errorf("areas should have different colors: %+v", areas[:2])
+	}
+}
+
+func TestAssignCodeowners(t *testing.T) {
+	c, err := ParseCodeowners(strings.NewReader(testCodeowners))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	areas := ScanAreas(testRepositoryFiles, "area/")
+	AssignCodeowners(areas, testRepositoryFiles, c)
+	want := map[string][]string{
+		"api":       {"@org/backend"},
+		"docs":      nil,
+		"tools":     {"@bob", "@carol"},
+		"tools/gen": {"@bob", "@carol"},
+		"web":       {"@org/frontend"},
+	}
+	for _, a := range areas {
+		if !slices.Equal(a.Codeowners, want[a.Path]) {
+			t.Errorf("codeowners of %s = %v, want %v", a.Path, a.Codeowners, want[a.Path])
+		}
+	}
+}
+
+func TestFormatConfig(t *testing.T) {
+	areas := []Area{
+		{Label: "area/api", Path: "api", Color: "1d76db", Description: "Changes to api", Codeowners: []string{"@org/backend"}},
+		{Label: "area/it's", Path: "it's", Color: "0e8a16", Description: "Changes to it's"},
+	}
+	content := FormatConfig(areas)
+	cfg, err := LoadConfigFromReader(strings.NewReader(content), true)
+	if err != nil {
+		t.Fatalf("generated config should load in strict mode: %v\n%s", err, content)
+	}
+	api, ok := cfg["area/api"]
+	if !ok || api.Color != "1d76db" || api.Description != "Changes to api" || !slices.Equal(api.Codeowners, []string{"@org/backend"}) {
+		t.Errorf("unexpected config: %+v", api)
+	}
+	if globs := labelGlobs(cfg)["area/it's"]; !slices.Equal(globs, []string{"it's/**"}) {
+		t.Errorf("unexpected globs: %v", globs)
+	}
+}
@@ -1,7 +1,6 @@
 package labeler
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -11,10 +10,14 @@ import (
 	"github.com/cli/go-gh/v2/pkg/repository"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
 
 	"gopkg.in/yaml.v3"
 )
 
+// DefaultConfigPath is the default path of the labeler config file.
+var DefaultConfigPath = ".github/labeler.yml"
+
 func LoadConfigFromReader(r io.Reader, strictMode bool) (LabelerConfig, error) {
 	// Read all content into a buffer so we can decode it twice if needed
 	data, err := io.ReadAll(r)
@@ -22,11 +25,19 @@ func LoadConfigFromReader(r io.Reader, strictMode bool) (LabelerConfig, error) {
 		return nil, err
 	}
 
+	// Read the top-level ignore list, which is skipped when decoding the rest of the document that maps labels to their rules
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	ignore, hasIgnore, err := findIgnore(&doc)
+	if err != nil {
+		return nil, err
+	}
+
 	// First pass: try strict decoding to detect unknown fields
-	var cfgStrict labelerYamlConfig
-	decoder := yaml.NewDecoder(bytes.NewReader(data))
-	decoder.KnownFields(true)
-	if err := decoder.Decode(&cfgStrict); err != nil {
+	cfgStrict, err := decodeLabels(data, hasIgnore, true)
+	if err != nil {
 		// Check if it's an unknown field error
 		if strings.Contains(err.Error(), "field") && strings.Contains(err.Error(), "not found") {
 			if strictMode {
@@ -37,12 +48,17 @@ func LoadConfigFromReader(r io.Reader, strictMode bool) (LabelerConfig, error) {
 			logger.Warn("Unknown fields will be ignored. Please check the labeler configuration documentation")
 
 			// Second pass: decode normally (allowing unknown fields)
-			var cfg labelerYamlConfig
-			if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&cfg); err != nil {
+			cfg, err := decodeLabels(data, hasIgnore, false)
+			if err != nil {
 				return nil, err
 			}
 			logger.Debug("Config loaded successfully", "labels", len(cfg))
-			return cfg.GetConfig(), nil
+			result := cfg.GetConfig()
+			result.AddIgnoreFiles(ignore)
+			if err := validateConfig(result); err != nil {
+				return nil, fmt.Errorf("config validation failed: %w", err)
+			}
+			return result, nil
 		}
 		// If it's not an unknown field error, return it as actual error
 		return nil, err
@@ -50,7 +66,12 @@ func LoadConfigFromReader(r io.Reader, strictMode bool) (LabelerConfig, error) {
 
 	// Successfully loaded with strict validation
 	logger.Debug("Config loaded successfully", "labels", len(cfgStrict))
-	return cfgStrict.GetConfig(), nil
+	result := cfgStrict.GetConfig()
+	result.AddIgnoreFiles(ignore)
+	if err := validateConfig(result); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+	return result, nil
 }
 
 // ConfigFileExists checks if the config file exists at the given path.
@@ -106,3 +127,40 @@ func LoadConfigFromRepo(ctx context.Context, g *gh.GitHubClient, repo repository
 	logger.Debug("Successfully loaded config from repository", "owner", repo.Owner, "repo", repo.Name, "path", path, "ref", refStr, "labels", len(cfg))
 	return cfg, nil
 }
+
+// LoadConfigFromPath loads a labeler config from a local file if it exists, otherwise from the repository.
+// configPath accepts a local path, a path in the repository, a GitHub URL, or the actions uses format (owner/repo[/path]@ref).
+// If ref is empty, GITHUB_SHA is used, then the head branch of headRefPR if it is not empty, falling back to the default branch.
+func LoadConfigFromPath(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, configPath string, ref string, headRefPR string, skipLocalConfig bool, strictMode bool) (LabelerConfig, error) {
+	if !skipLocalConfig && ConfigFileExists(configPath) {
+		// If local config exists but failed to load, don't fallback to remote config
+		return LoadConfig(configPath, strictMode)
+	}
+	if ref == "" {
+		ref = os.Getenv("GITHUB_SHA")
+	}
+	contentPaths, err := parser.ParseContentPath(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config path: %w", err)
+	}
+	if contentPaths.Ref == nil && contentPaths.Repo == nil {
+		if ref == "" && headRefPR != "" {
+			pr, err := gh.GetPullRequest(ctx, g, repo, headRefPR)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get PR %s to resolve ref: %w", headRefPR, err)
+			}
+			if pr.GetHead().GetRef() != "" {
+				ref = pr.GetHead().GetRef()
+				logger.Info("Using PR head branch as ref", "pr", headRefPR, "ref", ref)
+			}
+		}
+		contentPaths.Ref = &ref
+	}
+	if contentPaths.Repo == nil {
+		contentPaths.Repo = &repo
+	}
+	if contentPaths.Path == nil {
+		contentPaths.Path = &DefaultConfigPath
+	}
+	return LoadConfigFromRepo(ctx, g, *contentPaths.Repo, *contentPaths.Path, contentPaths.Ref, strictMode)
+}
@@ -0,0 +1,92 @@
+package labeler
+
+import (
+	"context"
+	"slices"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// ManualOverrides are the labels whose last change on the PR was made by a human rather than automation.
+type ManualOverrides struct {
+	Added   []string // Labels last added by a human
+	Removed []string // Labels last removed by a human
+}
+
+// NewManualOverrides derives the manual overrides from the label events of the PR in chronological order.
+func NewManualOverrides(events []labels.LabelEvent) ManualOverrides {
+	last := make(map[string]labels.LabelEvent)
+	for _, e := range events {
+		last[labels.NormalizeName(e.Label)] = e
+	}
+	overrides := ManualOverrides{}
+	for _, e := range last {
+		if e.Automation {
+			continue
+		}
+		switch e.Action {
+		case labels.LabelActionAdded:
+			overrides.Added = append(overrides.Added, e.Label)
+		case labels.LabelActionRemoved:
+			overrides.Removed = append(overrides.Removed, e.Label)
+		}
+	}
+	slices.Sort(overrides.Added)
+	slices.Sort(overrides.Removed)
+	return overrides
+}
+
+// GetManualOverrides reads the PR timeline and returns the labels a human last added or removed.
+func GetManualOverrides(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, pr *PullRequest, automation *labels.AutomationMatcher) (ManualOverrides, error) {
+	events, err := labels.ListLabelHistory(ctx, g, repo, pr.GetNumber(), automation)
+	if err != nil {
+		return ManualOverrides{}, err
+	}
+	overrides := NewManualOverrides(events)
+	logger.Debug("Manual label overrides", "pr", pr.GetNumber(), "added", overrides.Added, "removed", overrides.Removed)
+	return overrides, nil
+}
+
+// HasRespectManual returns whether any label in the config respects manual overrides.
+func (c LabelerConfig) HasRespectManual() bool {
+	for _, l := range c {
+		if l.RespectManual {
+			return true
+		}
+	}
+	return false
+}
+
+// RespectManual returns the result with the manual overrides applied to the labels that respect them:
+// a label a human removed is not matched again, and a label a human added is not unmatched.
+// If all is true, every label respects manual overrides regardless of the config.
+func (r MatchResult) RespectManual(cfg LabelerConfig, overrides ManualOverrides, all bool) MatchResult {
+	respects := func(name string, overridden []string) bool {
+		if !all && !cfg[name].RespectManual {
+			return false
+		}
+		return slices.ContainsFunc(overridden, func(o string) bool {
+			return labels.NormalizeName(o) == labels.NormalizeName(name)
+		})
+	}
+	return MatchResult{
+		Current: r.Current,
+		Matched: slices.DeleteFunc(slices.Clone(r.Matched), func(name string) bool {
+			if respects(name, overrides.Removed) {
+				logger.Debug("Keeping label removed by a human", "label", name)
+				return true
+			}
+			return false
+		}),
+		Unmatched: slices.DeleteFunc(slices.Clone(r.Unmatched), func(name string) bool {
+			if respects(name, overrides.Added) {
+				logger.Debug("Keeping label added by a human", "label", name)
+				return true
+			}
+			return false
+		}),
+	}
+}
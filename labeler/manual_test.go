@@ -0,0 +1,84 @@
+package labeler
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/srz-zumix/gh-label-kit/labels"
+)
+
+func TestLoadConfig_RespectManual(t *testing.T) {
+	yamlContent := `
+backend:
+  - changed-files:
+    - any-glob-to-any-file: 'api/**'
+  - respect-manual: true
+frontend:
+  - changed-files:
+    - any-glob-to-any-file: 'web/**'
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(yamlContent), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	if !cfg["backend"].RespectManual {
+		t.Errorf("backend should respect manual overrides")
+	}
+	if cfg["frontend"].RespectManual {
+		t.Errorf("frontend should not respect manual overrides")
+	}
+	if len(cfg["backend"].Matcher) != 1 {
+		t.Errorf("expected 1 matcher for backend, got %d", len(cfg["backend"].Matcher))
+	}
+	if !cfg.HasRespectManual() {
+		t.Errorf("HasRespectManual should be true")
+	}
+}
+
+func TestNewManualOverrides(t *testing.T) {
+	events := []labels.LabelEvent{
+		{Action: labels.LabelActionAdded, Label: "bug", Automation: true},
+		{Action: labels.LabelActionRemoved, Label: "bug", Actor: "octocat"},
+		{Action: labels.LabelActionAdded, Label: "docs", Actor: "octocat"},
+		{Action: labels.LabelActionRemoved, Label: "ci", Actor: "octocat"},
+		{Action: labels.LabelActionAdded, Label: "CI", Automation: true},
+	}
+	overrides := NewManualOverrides(events)
+	if !slices.Equal(overrides.Removed, []string{"bug"}) {
+		t.Errorf("unexpected removed: %v", overrides.Removed)
+	}
+	if !slices.Equal(overrides.Added, []string{"docs"}) {
+		t.Errorf("unexpected added: %v", overrides.Added)
+	}
+}
+
+func TestMatchResult_RespectManual(t *testing.T) {
+	cfg := LabelerConfig{
+		"bug":      LabelerLabelConfig{RespectManual: true},
+		"docs":     LabelerLabelConfig{RespectManual: true},
+		"frontend": LabelerLabelConfig{},
+	}
+	result := MatchResult{
+		Current:   []string{"docs", "frontend"},
+		Matched:   []string{"bug"},
+		Unmatched: []string{"docs", "frontend"},
+	}
+	overrides := ManualOverrides{Added: []string{"Docs", "frontend"}, Removed: []string{"bug"}}
+
+	got := result.RespectManual(cfg, overrides, false)
+	if !slices.Equal(got.SyncTo(), []string{"docs"}) {
+		t.Errorf("SyncTo() = %v, want [docs]", got.SyncTo())
+	}
+	if got.HasDiff(false) {
+		t.Errorf("label removed by a human should not be added again")
+	}
+
+	got = result.RespectManual(cfg, overrides, true)
+	if !slices.Equal(got.SyncTo(), []string{"docs", "frontend"}) {
+		t.Errorf("SyncTo() = %v, want [docs frontend]", got.SyncTo())
+	}
+	if !slices.Equal(result.Matched, []string{"bug"}) {
+		t.Errorf("original result should not be modified: %v", result.Matched)
+	}
+}
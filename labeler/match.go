@@ -4,27 +4,76 @@ import (
 	"context"
 	"maps"
 	"slices"
+	"strings"
 
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/gh-label-kit/labels"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 	"github.com/srz-zumix/go-gh-extension/pkg/logger"
 )
 
 // Matcher handles all matching logic for labeler config rules
 type Matcher struct {
+	ctx           context.Context
+	g             *gh.GitHubClient
 	authorMatcher *AuthorMatcher
+	// codeowners caches the CODEOWNERS of each base repository and branch, keyed by "owner/repo@ref"
+	codeowners map[string]*Codeowners
+	// fixedCodeowners, if set, is used for every PR instead of fetching CODEOWNERS
+	fixedCodeowners *Codeowners
+	commandOptions  CommandOptions
+	// commandResults caches the results of command rules, keyed by command, PR number and changed files,
+	// and the commands that were skipped because they are not allowed, keyed by command
+	commandResults map[string]matchState
 }
 
 // NewMatcher creates a new Matcher instance with the given context and GitHub client
 func NewMatcher(ctx context.Context, g *gh.GitHubClient) *Matcher {
 	return &Matcher{
-		authorMatcher: NewAuthorMatcher(ctx, g),
+		ctx:            ctx,
+		g:              g,
+		authorMatcher:  NewAuthorMatcher(ctx, g),
+		codeowners:     make(map[string]*Codeowners),
+		commandOptions: CommandOptions{Timeout: DefaultCommandTimeout},
+		commandResults: make(map[string]matchState),
 	}
 }
 
+// SetCodeowners makes the codeowner rules use the given CODEOWNERS for every PR instead of fetching it from the base branch.
+func (m *Matcher) SetCodeowners(c *Codeowners) {
+	m.fixedCodeowners = c
+}
+
+// SetCommandOptions sets how the command rules and label commands are run.
+func (m *Matcher) SetCommandOptions(o CommandOptions) {
+	m.commandOptions = o
+}
+
+// codeownersOf returns the CODEOWNERS of the base branch of the PR, as GitHub resolves owners from the base branch.
+// If it cannot be fetched, a warning is logged and no file has owners.
+func (m *Matcher) codeownersOf(pr *PullRequest) *Codeowners {
+	if m.fixedCodeowners != nil || m.g == nil || m.ctx == nil {
+		return m.fixedCodeowners
+	}
+	repo := repository.Repository{Owner: pr.GetBase().GetRepo().GetOwner().GetLogin(), Name: pr.GetBase().GetRepo().GetName()}
+	ref := pr.GetBase().GetRef()
+	key := repo.Owner + "/" + repo.Name + "@" + ref
+	if c, ok := m.codeowners[key]; ok {
+		return c
+	}
+	c, err := FetchCodeowners(m.ctx, m.g, repo, ref)
+	if err != nil {
+		logger.Warn("Failed to fetch CODEOWNERS, codeowner rules will not match", "repo", repo.Owner+"/"+repo.Name, "ref", ref, "error", err)
+	}
+	m.codeowners[key] = c
+	return c
+}
+
 type MatchResult struct {
 	Current   []string // Current labels on the PR
 	Matched   []string // Matched label names
 	Unmatched []string // Unmatched label names
+	Failed    []string // Label names whose conditions failed to evaluate, which are neither added nor removed
 }
 
 func (r MatchResult) GetLabels(sync bool) []string {
@@ -34,6 +83,19 @@ func (r MatchResult) GetLabels(sync bool) []string {
 	return r.SetTo()
 }
 
+// AddMatched returns the result with the labels added to the matched labels, such as labels from label commands.
+func (r MatchResult) AddMatched(labels []string) MatchResult {
+	r.Matched = slices.Clone(r.Matched)
+	for _, label := range labels {
+		if !slices.Contains(r.Matched, label) {
+			r.Matched = append(r.Matched, label)
+		}
+	}
+	slices.Sort(r.Matched)
+	r.Unmatched = slices.DeleteFunc(slices.Clone(r.Unmatched), func(l string) bool { return slices.Contains(labels, l) })
+	return r
+}
+
 func (r MatchResult) IsMatched(label string) bool {
 	for _, matched := range r.Matched {
 		if matched == label {
@@ -132,6 +194,17 @@ func (r MatchResult) DeleteTo() []string {
 
 // CheckMatchConfigs checks all label configs against the PR and returns matched/unmatched labels
 func (m *Matcher) CheckMatchConfigs(cfg LabelerConfig, changedFiles []*CommitFile, pr *PullRequest) MatchResult {
+	result, _ := m.checkMatchConfigs(cfg, changedFiles, pr, false)
+	return result
+}
+
+// ExplainMatchConfigs checks all label configs against the PR like CheckMatchConfigs, and also returns how each label
+// was evaluated, sorted by label name.
+func (m *Matcher) ExplainMatchConfigs(cfg LabelerConfig, changedFiles []*CommitFile, pr *PullRequest) (MatchResult, []*labels.MatchTrace) {
+	return m.checkMatchConfigs(cfg, changedFiles, pr, true)
+}
+
+func (m *Matcher) checkMatchConfigs(cfg LabelerConfig, changedFiles []*CommitFile, pr *PullRequest, explain bool) (MatchResult, []*labels.MatchTrace) {
 	logger.Debug("Starting label matching", "pr", pr.GetNumber(), "changedFiles", len(changedFiles), "configLabels", len(cfg))
 	result := MatchResult{
 		Current:   []string{},
@@ -143,121 +216,291 @@ func (m *Matcher) CheckMatchConfigs(cfg LabelerConfig, changedFiles []*CommitFil
 		result.Current = append(result.Current, label.GetName())
 	}
 
+	var traces []*labels.MatchTrace
 	for label, labelConfig := range cfg {
-		matched := len(labelConfig.Matcher) != 0
+		var t *tracer
+		if explain {
+			t = newTracer(label)
+			traces = append(traces, t.node)
+		}
+		state := stateOf(len(labelConfig.Matcher) != 0)
 		logger.Debug("Checking label config", "label", label, "matcherCount", len(labelConfig.Matcher))
+		files := filterIgnoredFiles(labelConfig.IgnoreFiles, changedFiles)
 		for i, match := range labelConfig.Matcher {
-			isMatch := m.matchLabelerMatch(match, changedFiles, pr)
-			logger.Debug("Matcher result", "label", label, "matcherIndex", i, "matched", isMatch)
-			if !isMatch {
-				matched = false
+			matchState := m.matchLabelerMatch(match, files, pr, t)
+			logger.Debug("Matcher result", "label", label, "matcherIndex", i, "state", matchState)
+			if state = state.and(matchState); state == stateUnmatched {
 				break
 			}
 		}
-		if matched {
+		t.done(state)
+		switch state {
+		case stateMatched:
 			logger.Debug("Label matched", "label", label)
 			result.Matched = append(result.Matched, label)
-		} else {
+		case stateUnmatched:
 			logger.Debug("Label unmatched", "label", label)
 			result.Unmatched = append(result.Unmatched, label)
+		default:
+			logger.Warn("Conditions of label failed to evaluate, the label is neither added nor removed", "label", label, "pr", pr.GetNumber())
+			result.Failed = append(result.Failed, label)
 		}
 	}
 	slices.Sort(result.Current)
 	slices.Sort(result.Matched)
 	slices.Sort(result.Unmatched)
-	logger.Debug("Label matching completed", "pr", pr.GetNumber(), "current", result.Current, "matched", result.Matched, "unmatched", result.Unmatched)
-	return result
+	slices.Sort(result.Failed)
+	slices.SortFunc(traces, func(a, b *labels.MatchTrace) int { return strings.Compare(a.Name, b.Name) })
+	logger.Debug("Label matching completed", "pr", pr.GetNumber(), "current", result.Current, "matched", result.Matched, "unmatched", result.Unmatched, "failed", result.Failed)
+	return result, traces
+}
+
+// matchState is the result of a condition. A condition that fails to evaluate, such as a when expression with a runtime
+// error or a command that fails, is neither matched nor unmatched, so that not and none do not turn the failure into a match.
+type matchState int
+
+const (
+	stateUnmatched matchState = iota
+	stateMatched
+	stateFailed
+)
+
+func stateOf(matched bool) matchState {
+	if matched {
+		return stateMatched
+	}
+	return stateUnmatched
+}
+
+func (s matchState) String() string {
+	switch s {
+	case stateMatched:
+		return "matched"
+	case stateUnmatched:
+		return "unmatched"
+	}
+	return "failed"
+}
+
+// or combines the states of conditions of which any must match: a match decides, otherwise a failure does.
+func (s matchState) or(other matchState) matchState {
+	switch {
+	case s == stateMatched || other == stateMatched:
+		return stateMatched
+	case s == stateFailed || other == stateFailed:
+		return stateFailed
+	}
+	return stateUnmatched
+}
+
+// and combines the states of conditions of which all must match: a mismatch decides, otherwise a failure does.
+func (s matchState) and(other matchState) matchState {
+	switch {
+	case s == stateUnmatched || other == stateUnmatched:
+		return stateUnmatched
+	case s == stateFailed || other == stateFailed:
+		return stateFailed
+	}
+	return stateMatched
+}
+
+// not negates the state. A failure stays a failure.
+func (s matchState) not() matchState {
+	switch s {
+	case stateMatched:
+		return stateUnmatched
+	case stateUnmatched:
+		return stateMatched
+	}
+	return stateFailed
 }
 
 // matchLabelerMatch checks if a PR matches a label's match object (any/all/changed-files/branch/author)
-func (m *Matcher) matchLabelerMatch(match LabelerMatch, changedFiles []*CommitFile, pr *PullRequest) bool {
+func (m *Matcher) matchLabelerMatch(match LabelerMatch, changedFiles []*CommitFile, pr *PullRequest, t *tracer) matchState {
+	state := stateMatched
 	if len(match.All) > 0 {
-		if !m.matchLabelerMatchAll(match.All, changedFiles, pr) {
-			return false
+		if state = state.and(m.matchLabelerMatchAll(match.All, changedFiles, pr, t.child("all"))); state == stateUnmatched {
+			return state
 		}
 	}
 	if len(match.Any) > 0 {
-		if !m.matchLabelerMatchAny(match.Any, changedFiles, pr) {
-			return false
-		}
+		state = state.and(m.matchLabelerMatchAny(match.Any, changedFiles, pr, t.child("any")))
 	}
-	return true
+	return state
 }
 
-func (m *Matcher) matchLabelerMatchAny(rules []LabelerRule, changedFiles []*CommitFile, pr *PullRequest) bool {
+func (m *Matcher) matchLabelerMatchAny(rules []LabelerRule, changedFiles []*CommitFile, pr *PullRequest, t *tracer) matchState {
+	state := stateUnmatched
 	for _, rule := range rules {
-		if m.matchLabelerRuleAny(rule, changedFiles, pr) {
-			return true
+		if state = state.or(m.matchLabelerRuleAny(rule, changedFiles, pr, t)); state == stateMatched {
+			break
 		}
 	}
-	return false
+	return t.done(state)
 }
 
-func (m *Matcher) matchLabelerMatchAll(rules []LabelerRule, changedFiles []*CommitFile, pr *PullRequest) bool {
+func (m *Matcher) matchLabelerMatchAll(rules []LabelerRule, changedFiles []*CommitFile, pr *PullRequest, t *tracer) matchState {
+	state := stateMatched
 	for _, rule := range rules {
-		if !m.matchLabelerRuleAll(rule, changedFiles, pr) {
-			return false
+		if state = state.and(m.matchLabelerRuleAll(rule, changedFiles, pr, t)); state == stateUnmatched {
+			break
 		}
 	}
-	return true
+	return t.done(state)
 }
 
-func (m *Matcher) matchLabelerRuleAny(r LabelerRule, changedFiles []*CommitFile, pr *PullRequest) bool {
+// matchLabelerMatchNot checks that not all of the rules match
+func (m *Matcher) matchLabelerMatchNot(rules []LabelerRule, changedFiles []*CommitFile, pr *PullRequest, t *tracer) matchState {
+	return t.done(m.matchLabelerMatchAll(rules, changedFiles, pr, t).not())
+}
+
+// matchLabelerMatchNone checks that none of the rules matches
+func (m *Matcher) matchLabelerMatchNone(rules []LabelerRule, changedFiles []*CommitFile, pr *PullRequest, t *tracer) matchState {
+	return t.done(m.matchLabelerMatchAny(rules, changedFiles, pr, t).not())
+}
+
+func (m *Matcher) matchLabelerRuleAny(r LabelerRule, changedFiles []*CommitFile, pr *PullRequest, t *tracer) matchState {
+	state := stateUnmatched
+	if len(r.Any) > 0 {
+		if state = state.or(m.matchLabelerMatchAny(r.Any, changedFiles, pr, t.child("any"))); state == stateMatched {
+			logger.Debug("Nested any rule matched (any)", "pr", pr.GetNumber())
+			return state
+		}
+	}
+	if len(r.All) > 0 {
+		if state = state.or(m.matchLabelerMatchAll(r.All, changedFiles, pr, t.child("all"))); state == stateMatched {
+			logger.Debug("Nested all rule matched (any)", "pr", pr.GetNumber())
+			return state
+		}
+	}
+	if len(r.Not) > 0 {
+		if state = state.or(m.matchLabelerMatchNot(r.Not, changedFiles, pr, t.child("not"))); state == stateMatched {
+			logger.Debug("Nested not rule matched (any)", "pr", pr.GetNumber())
+			return state
+		}
+	}
+	if len(r.None) > 0 {
+		if state = state.or(m.matchLabelerMatchNone(r.None, changedFiles, pr, t.child("none"))); state == stateMatched {
+			logger.Debug("Nested none rule matched (any)", "pr", pr.GetNumber())
+			return state
+		}
+	}
 	if r.BaseBranch != nil {
-		if matchLabelerRuleBaseBranch(r, pr) {
+		if state = state.or(t.leaf("base-branch", r.GetBaseBranch(), stateOf(matchLabelerRuleBaseBranch(r, pr)))); state == stateMatched {
 			logger.Debug("BaseBranch rule matched (any)", "pr", pr.GetNumber(), "baseBranch", pr.Base.GetRef())
-			return true
+			return state
 		}
 	}
 	if r.HeadBranch != nil {
-		if matchLabelerRuleHeadBranch(r, pr) {
+		if state = state.or(t.leaf("head-branch", r.GetHeadBranch(), stateOf(matchLabelerRuleHeadBranch(r, pr)))); state == stateMatched {
 			logger.Debug("HeadBranch rule matched (any)", "pr", pr.GetNumber(), "headBranch", pr.Head.GetRef())
-			return true
+			return state
 		}
 	}
 	if r.Author != nil {
-		if m.matchLabelerRuleAuthor(r, pr) {
+		if state = state.or(t.leaf("author", r.GetAuthor(), stateOf(m.matchLabelerRuleAuthor(r, pr)))); state == stateMatched {
 			logger.Debug("Author rule matched (any)", "pr", pr.GetNumber(), "author", pr.GetUser().GetLogin())
-			return true
+			return state
 		}
 	}
-	if len(r.ChangedFiles) > 0 {
-		if matchChangedFilesAny(r.ChangedFiles, changedFiles) {
+	if r.Codeowner != nil {
+		if state = state.or(t.leaf("codeowner", r.GetCodeowner(), stateOf(m.matchLabelerRuleCodeowner(r, changedFiles, pr)))); state == stateMatched {
+			logger.Debug("Codeowner rule matched (any)", "pr", pr.GetNumber(), "codeowner", r.GetCodeowner())
+			return state
+		}
+	}
+	if r.When != "" {
+		if state = state.or(t.leaf("when", []string{r.When}, evalWhen(r.When, changedFiles, pr))); state == stateMatched {
+			logger.Debug("When rule matched (any)", "pr", pr.GetNumber(), "when", r.When)
+			return state
+		}
+	}
+	if r.Command != "" {
+		if state = state.or(t.leaf("command", []string{r.Command}, m.matchLabelerRuleCommand(r, changedFiles, pr))); state == stateMatched {
+			logger.Debug("Command rule matched (any)", "pr", pr.GetNumber(), "command", r.Command)
+			return state
+		}
+	}
+	for _, cf := range r.ChangedFiles {
+		if state = state.or(t.leaf("changed-files", describeChangedFilesRule(cf), stateOf(matchChangedFilesRuleAny(cf, changedFiles)))); state == stateMatched {
 			logger.Debug("ChangedFiles rule matched (any)", "pr", pr.GetNumber(), "changedFilesCount", len(changedFiles))
-			return true
+			return state
 		}
 	}
-	logger.Debug("No rules matched (any)", "pr", pr.GetNumber())
-	return false
+	logger.Debug("No rules matched (any)", "pr", pr.GetNumber(), "state", state)
+	return state
 }
 
-func (m *Matcher) matchLabelerRuleAll(r LabelerRule, changedFiles []*CommitFile, pr *PullRequest) bool {
+func (m *Matcher) matchLabelerRuleAll(r LabelerRule, changedFiles []*CommitFile, pr *PullRequest, t *tracer) matchState {
+	state := stateMatched
+	if len(r.Any) > 0 {
+		if state = state.and(m.matchLabelerMatchAny(r.Any, changedFiles, pr, t.child("any"))); state == stateUnmatched {
+			logger.Debug("Nested any rule not matched (all)", "pr", pr.GetNumber())
+			return state
+		}
+	}
+	if len(r.All) > 0 {
+		if state = state.and(m.matchLabelerMatchAll(r.All, changedFiles, pr, t.child("all"))); state == stateUnmatched {
+			logger.Debug("Nested all rule not matched (all)", "pr", pr.GetNumber())
+			return state
+		}
+	}
+	if len(r.Not) > 0 {
+		if state = state.and(m.matchLabelerMatchNot(r.Not, changedFiles, pr, t.child("not"))); state == stateUnmatched {
+			logger.Debug("Nested not rule not matched (all)", "pr", pr.GetNumber())
+			return state
+		}
+	}
+	if len(r.None) > 0 {
+		if state = state.and(m.matchLabelerMatchNone(r.None, changedFiles, pr, t.child("none"))); state == stateUnmatched {
+			logger.Debug("Nested none rule not matched (all)", "pr", pr.GetNumber())
+			return state
+		}
+	}
 	if r.BaseBranch != nil {
-		if !matchLabelerRuleBaseBranch(r, pr) {
+		if state = state.and(t.leaf("base-branch", r.GetBaseBranch(), stateOf(matchLabelerRuleBaseBranch(r, pr)))); state == stateUnmatched {
 			logger.Debug("BaseBranch rule not matched (all)", "pr", pr.GetNumber(), "baseBranch", pr.Base.GetRef())
-			return false
+			return state
 		}
 	}
 	if r.HeadBranch != nil {
-		if !matchLabelerRuleHeadBranch(r, pr) {
+		if state = state.and(t.leaf("head-branch", r.GetHeadBranch(), stateOf(matchLabelerRuleHeadBranch(r, pr)))); state == stateUnmatched {
 			logger.Debug("HeadBranch rule not matched (all)", "pr", pr.GetNumber(), "headBranch", pr.Head.GetRef())
-			return false
+			return state
 		}
 	}
 	if r.Author != nil {
-		if !m.matchLabelerRuleAuthor(r, pr) {
+		if state = state.and(t.leaf("author", r.GetAuthor(), stateOf(m.matchLabelerRuleAuthor(r, pr)))); state == stateUnmatched {
 			logger.Debug("Author rule not matched (all)", "pr", pr.GetNumber(), "author", pr.GetUser().GetLogin())
-			return false
+			return state
+		}
+	}
+	if r.Codeowner != nil {
+		if state = state.and(t.leaf("codeowner", r.GetCodeowner(), stateOf(m.matchLabelerRuleCodeowner(r, changedFiles, pr)))); state == stateUnmatched {
+			logger.Debug("Codeowner rule not matched (all)", "pr", pr.GetNumber(), "codeowner", r.GetCodeowner())
+			return state
+		}
+	}
+	if r.When != "" {
+		if state = state.and(t.leaf("when", []string{r.When}, evalWhen(r.When, changedFiles, pr))); state == stateUnmatched {
+			logger.Debug("When rule not matched (all)", "pr", pr.GetNumber(), "when", r.When)
+			return state
 		}
 	}
-	if len(r.ChangedFiles) > 0 {
-		if !matchChangedFilesAll(r.ChangedFiles, changedFiles) {
+	if r.Command != "" {
+		if state = state.and(t.leaf("command", []string{r.Command}, m.matchLabelerRuleCommand(r, changedFiles, pr))); state == stateUnmatched {
+			logger.Debug("Command rule not matched (all)", "pr", pr.GetNumber(), "command", r.Command)
+			return state
+		}
+	}
+	for _, cf := range r.ChangedFiles {
+		if state = state.and(t.leaf("changed-files", describeChangedFilesRule(cf), stateOf(matchChangedFilesRuleAll(cf, changedFiles)))); state == stateUnmatched {
 			logger.Debug("ChangedFiles rule not matched (all)", "pr", pr.GetNumber(), "changedFilesCount", len(changedFiles))
-			return false
+			return state
 		}
 	}
-	logger.Debug("All rules matched (all)", "pr", pr.GetNumber())
-	return true
+	logger.Debug("All rules matched (all)", "pr", pr.GetNumber(), "state", state)
+	return state
 }
 
 // matchLabelerRuleAuthor checks if the PR author matches the rule's author patterns
@@ -268,3 +511,21 @@ func (m *Matcher) matchLabelerRuleAuthor(r LabelerRule, pr *PullRequest) bool {
 	}
 	return m.authorMatcher.MatchAuthor(authors, pr)
 }
+
+// matchLabelerRuleCodeowner checks if any changed file is owned by the rule's owners per the CODEOWNERS of the base branch
+func (m *Matcher) matchLabelerRuleCodeowner(r LabelerRule, changedFiles []*CommitFile, pr *PullRequest) bool {
+	owners := r.GetCodeowner()
+	if len(owners) == 0 {
+		return false
+	}
+	return m.codeownersOf(pr).OwnedBy(filenames(changedFiles), owners)
+}
+
+// filenames returns the names of the changed files
+func filenames(changedFiles []*CommitFile) []string {
+	files := make([]string, 0, len(changedFiles))
+	for _, f := range changedFiles {
+		files = append(files, f.GetFilename())
+	}
+	return files
+}
@@ -0,0 +1,261 @@
+package labeler
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/gh-label-kit/labels"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// DatasetPullRequest is a merged pull request with its changed files and final labels.
+type DatasetPullRequest struct {
+	Number int      `json:"number"`
+	Labels []string `json:"labels"`
+	Files  []string `json:"files"`
+}
+
+// Dataset is the history of merged pull requests used to suggest rules. It is saved as JSON so that suggestions can be computed offline.
+type Dataset struct {
+	Repository   string               `json:"repository"`
+	PullRequests []DatasetPullRequest `json:"pullRequests"`
+}
+
+// LoadDataset loads a dataset from a JSON file.
+func LoadDataset(p string) (*Dataset, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var d Dataset
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("failed to parse dataset %s: %w", p, err)
+	}
+	logger.Debug("Dataset loaded successfully", "path", p, "pullRequests", len(d.PullRequests))
+	return &d, nil
+}
+
+// Save writes the dataset to a JSON file.
+func (d *Dataset) Save(p string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o644)
+}
+
+// FetchDataset collects the changed files and labels of up to limit recently merged pull requests.
+// The changed files are read through the cache.
+func FetchDataset(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, limit int, cache *FileCache) (*Dataset, error) {
+	prs, err := ListRecentClosedPullRequests(ctx, g, repo, limit)
+	if err != nil {
+		return nil, err
+	}
+	d := &Dataset{Repository: fmt.Sprintf("%s/%s", repo.Owner, repo.Name), PullRequests: []DatasetPullRequest{}}
+	for _, pr := range prs {
+		if pr.MergedAt == nil {
+			continue
+		}
+		files, err := cache.ListPullRequestFiles(ctx, g, repo, pr)
+		if err != nil {
+			return nil, err
+		}
+		entry := DatasetPullRequest{Number: pr.GetNumber(), Labels: []string{}, Files: []string{}}
+		for _, l := range pr.Labels {
+			entry.Labels = append(entry.Labels, l.GetName())
+		}
+		for _, f := range files {
+			entry.Files = append(entry.Files, f.GetFilename())
+		}
+		d.PullRequests = append(d.PullRequests, entry)
+	}
+	return d, nil
+}
+
+// SuggestOptions are the thresholds for suggesting a directory as a rule for a label.
+type SuggestOptions struct {
+	MinPrecision float64 // Minimum ratio of the PRs touching the directory that have the label
+	MinSupport   int     // Minimum number of PRs touching the directory that have the label
+	MaxDepth     int     // Maximum depth of the suggested directories
+}
+
+// DefaultSuggestOptions returns the default thresholds.
+func DefaultSuggestOptions() SuggestOptions {
+	return SuggestOptions{MinPrecision: 0.8, MinSupport: 3, MaxDepth: 3}
+}
+
+// RuleStats is how well a glob predicts a label in the dataset.
+// Precision is the ratio of the PRs matching the glob that have the label, and recall is the ratio of the PRs with the label that match the glob.
+type RuleStats struct {
+	Glob      string  `json:"glob,omitempty"`
+	Matched   int     `json:"matched"`
+	Support   int     `json:"support"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+}
+
+// Suggestion is a set of changed-files globs proposed for a label, with the stats of each glob and of all globs combined.
+type Suggestion struct {
+	Label string      `json:"label"`
+	Total int         `json:"total"`
+	Rules []RuleStats `json:"rules"`
+	RuleStats
+}
+
+// directoriesOf returns the directories of the files up to the depth, without duplicates.
+func directoriesOf(files []string, depth int) map[string]struct{} {
+	dirs := make(map[string]struct{})
+	for _, f := range files {
+		dir := path.Dir(f)
+		for dir != "." && dir != "/" {
+			if strings.Count(dir, "/") < depth {
+				dirs[dir] = struct{}{}
+			}
+			dir = path.Dir(dir)
+		}
+	}
+	return dirs
+}
+
+// ratio returns n/d, or 0 if d is 0.
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// Suggest proposes directory globs for each label from the dataset. A directory is proposed for a label if enough of the
+// PRs touching it have the label; a directory is not proposed if one of its parent directories is already proposed for the label.
+// Labels without any proposed directory are omitted. Suggestions are sorted by label name.
+func Suggest(d *Dataset, opts SuggestOptions) []Suggestion {
+	// touched[dir] is the PRs touching the directory, and labeled[label] is the PRs with the label.
+	touched := make(map[string][]int)
+	labeled := make(map[string][]int)
+	names := make(map[string]string)
+	for i, pr := range d.PullRequests {
+		for dir := range directoriesOf(pr.Files, opts.MaxDepth) {
+			touched[dir] = append(touched[dir], i)
+		}
+		seen := make(map[string]struct{})
+		for _, l := range pr.Labels {
+			key := labels.NormalizeName(l)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			if _, ok := names[key]; !ok {
+				names[key] = l
+			}
+			labeled[key] = append(labeled[key], i)
+		}
+	}
+	dirs := make([]string, 0, len(touched))
+	for dir := range touched {
+		dirs = append(dirs, dir)
+	}
+	// Parents sort before their children, so that a proposed parent suppresses its children.
+	slices.Sort(dirs)
+
+	var suggestions []Suggestion
+	for key, prs := range labeled {
+		has := make(map[int]struct{}, len(prs))
+		for _, i := range prs {
+			has[i] = struct{}{}
+		}
+		var rules []RuleStats
+		var chosen []string
+		covered := make(map[int]struct{})
+		for _, dir := range dirs {
+			if slices.ContainsFunc(chosen, func(parent string) bool { return strings.HasPrefix(dir, parent+"/") }) {
+				continue
+			}
+			support := 0
+			for _, i := range touched[dir] {
+				if _, ok := has[i]; ok {
+					support++
+				}
+			}
+			precision := ratio(support, len(touched[dir]))
+			if support < opts.MinSupport || precision < opts.MinPrecision {
+				continue
+			}
+			chosen = append(chosen, dir)
+			for _, i := range touched[dir] {
+				covered[i] = struct{}{}
+			}
+			rules = append(rules, RuleStats{
+				Glob:      dir + "/**",
+				Matched:   len(touched[dir]),
+				Support:   support,
+				Precision: precision,
+				Recall:    ratio(support, len(prs)),
+			})
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		support := 0
+		for i := range covered {
+			if _, ok := has[i]; ok {
+				support++
+			}
+		}
+		suggestions = append(suggestions, Suggestion{
+			Label: names[key],
+			Total: len(prs),
+			Rules: rules,
+			RuleStats: RuleStats{
+				Matched:   len(covered),
+				Support:   support,
+				Precision: ratio(support, len(covered)),
+				Recall:    ratio(support, len(prs)),
+			},
+		})
+	}
+	slices.SortFunc(suggestions, func(a, b Suggestion) int {
+		return cmp.Compare(labels.NormalizeName(a.Label), labels.NormalizeName(b.Label))
+	})
+	return suggestions
+}
+
+// yamlQuote quotes the string as a single-quoted YAML scalar.
+func yamlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// yamlKey returns the string as a YAML mapping key, quoted only if needed.
+func yamlKey(s string) string {
+	data, err := yaml.Marshal(s)
+	if err != nil || strings.Contains(strings.TrimSuffix(string(data), "\n"), "\n") {
+		return yamlQuote(s)
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// FormatSuggestions renders the suggestions as a labeler.yml fragment with the stats as comments.
+func FormatSuggestions(suggestions []Suggestion) string {
+	var b strings.Builder
+	for i, s := range suggestions {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "# precision %.0f%% (%d/%d PRs), recall %.0f%% (%d/%d PRs)\n", s.Precision*100, s.Support, s.Matched, s.Recall*100, s.Support, s.Total)
+		fmt.Fprintf(&b, "%s:\n", yamlKey(s.Label))
+		b.WriteString("  - changed-files:\n")
+		b.WriteString("    - any-glob-to-any-file:\n")
+		for _, r := range s.Rules {
+			fmt.Fprintf(&b, "      - %s # precision %.0f%% (%d/%d), recall %.0f%%\n", yamlQuote(r.Glob), r.Precision*100, r.Support, r.Matched, r.Recall*100)
+		}
+	}
+	return b.String()
+}
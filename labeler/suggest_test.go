@@ -0,0 +1,81 @@
+package labeler
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testDataset() *Dataset {
+	return &Dataset{PullRequests: []DatasetPullRequest{
+		{Number: 1, Labels: []string{"backend"}, Files: []string{"api/server.go", "api/handler/user.go"}},
+		{Number: 2, Labels: []string{"backend"}, Files: []string{"api/handler/item.go"}},
+		{Number: 3, Labels: []string{"Backend", "docs"}, Files: []string{"api/server.go", "docs/api.md"}},
+		{Number: 4, Labels: []string{"docs"}, Files: []string{"docs/index.md", "README.md"}},
+		{Number: 5, Labels: []string{"docs"}, Files: []string{"docs/guide/setup.md"}},
+		{Number: 6, Labels: []string{}, Files: []string{"api/server.go"}},
+		{Number: 7, Labels: []string{"backend"}, Files: []string{"api/handler/order.go"}},
+		{Number: 8, Labels: []string{"docs"}, Files: []string{"docs/index.md"}},
+	}}
+}
+
+func TestSuggest(t *testing.T) {
+	suggestions := Suggest(testDataset(), SuggestOptions{MinPrecision: 0.75, MinSupport: 3, MaxDepth: 2})
+	if len(suggestions) != 2 {
+		t.Fatalf("expected 2 suggestions, got %+v", suggestions)
+	}
+	backend := suggestions[0]
+	if backend.Label != "backend" || len(backend.Rules) != 1 || backend.Rules[0].Glob != "api/**" {
+		t.Fatalf("unexpected suggestion: %+v", backend)
+	}
+	if r := backend.Rules[0]; r.Matched != 5 || r.Support != 4 || r.Precision != 0.8 || r.Recall != 1 {
+		t.Errorf("unexpected stats: %+v", r)
+	}
+	docs := suggestions[1]
+	if docs.Label != "docs" || len(docs.Rules) != 1 || docs.Rules[0].Glob != "docs/**" {
+		t.Errorf("child directories should not be suggested when the parent is: %+v", docs)
+	}
+	if docs.Support != 4 || docs.Total != 4 || docs.Precision != 1 {
+		t.Errorf("unexpected combined stats: %+v", docs.RuleStats)
+	}
+
+	// With a higher precision threshold, api/** is too broad but api/handler/** still qualifies.
+	suggestions = Suggest(testDataset(), SuggestOptions{MinPrecision: 0.9, MinSupport: 3, MaxDepth: 2})
+	if len(suggestions) != 2 || suggestions[0].Rules[0].Glob != "api/handler/**" || suggestions[0].Recall != 0.75 {
+		t.Errorf("unexpected suggestions: %+v", suggestions)
+	}
+}
+
+func TestFormatSuggestions(t *testing.T) {
+	fragment := FormatSuggestions(Suggest(testDataset(), SuggestOptions{MinPrecision: 0.75, MinSupport: 3, MaxDepth: 2}))
+	cfg, err := LoadConfigFromReader(strings.NewReader(fragment), true)
+	if err != nil {
+		t.Fatalf("fragment should be a valid config: %v\n%s", err, fragment)
+	}
+	if len(cfg) != 2 || len(cfg["backend"].Matcher) != 1 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	fragment = FormatSuggestions([]Suggestion{{Label: "type: docs", Rules: []RuleStats{{Glob: "docs/**"}}}})
+	cfg, err = LoadConfigFromReader(strings.NewReader(fragment), true)
+	if err != nil {
+		t.Fatalf("fragment should be a valid config: %v\n%s", err, fragment)
+	}
+	if _, ok := cfg["type: docs"]; !ok {
+		t.Errorf("label name should be quoted: %s", fragment)
+	}
+}
+
+func TestDataset_SaveLoad(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "dataset.json")
+	if err := testDataset().Save(p); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	d, err := LoadDataset(p)
+	if err != nil {
+		t.Fatalf("LoadDataset error: %v", err)
+	}
+	if len(d.PullRequests) != 8 || d.PullRequests[0].Files[1] != "api/handler/user.go" {
+		t.Errorf("unexpected dataset: %+v", d)
+	}
+}
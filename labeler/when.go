@@ -0,0 +1,144 @@
+package labeler
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/google/cel-go/parser"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// whenCostLimit bounds the cost of evaluating a when expression, so that a config cannot make the labeler hang.
+const whenCostLimit = 1_000_000
+
+// whenPullRequest is the pull request given to when expressions as "pr", and to external commands as JSON.
+type whenPullRequest struct {
+	Number    int      `cel:"number" json:"number"`
+	Title     string   `cel:"title" json:"title"`
+	Author    string   `cel:"author" json:"author"`
+	Base      string   `cel:"base" json:"base"`
+	Head      string   `cel:"head" json:"head"`
+	Labels    []string `cel:"labels" json:"labels"`
+	Draft     bool     `cel:"draft" json:"draft"`
+	Additions int      `cel:"additions" json:"additions"`
+	Deletions int      `cel:"deletions" json:"deletions"`
+}
+
+func newWhenPullRequest(pr *PullRequest) *whenPullRequest {
+	w := &whenPullRequest{
+		Number:    pr.GetNumber(),
+		Title:     pr.GetTitle(),
+		Author:    pr.GetUser().GetLogin(),
+		Base:      pr.GetBase().GetRef(),
+		Head:      pr.GetHead().GetRef(),
+		Labels:    []string{},
+		Draft:     pr.GetDraft(),
+		Additions: pr.GetAdditions(),
+		Deletions: pr.GetDeletions(),
+	}
+	for _, l := range pr.Labels {
+		w.Labels = append(w.Labels, l.GetName())
+	}
+	return w
+}
+
+// whenEnv is the CEL environment of when expressions. Expressions can only read the pull request and its changed files,
+// and "any" is available as an alias of the "exists" macro.
+var whenEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		ext.NativeTypes(reflect.TypeFor[whenPullRequest](), ext.ParseStructTags(true)),
+		cel.Variable("pr", cel.ObjectType("labeler.whenPullRequest")),
+		cel.Variable("files", cel.ListType(cel.StringType)),
+		cel.Macros(parser.NewReceiverMacro("any", 2, parser.MakeExists)),
+		ext.Strings(),
+	)
+})
+
+// whenPrograms caches the compiled when expressions by their source.
+var whenPrograms sync.Map
+
+// compileWhen type-checks the when expression and returns its program. The expression must evaluate to a bool.
+func compileWhen(expr string) (cel.Program, error) {
+	if p, ok := whenPrograms.Load(expr); ok {
+		return p.(cel.Program), nil
+	}
+	env, err := whenEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create when expression environment: %w", err)
+	}
+	ast, issues := env.Compile(expr)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid when expression %q: %w", expr, issues.Err())
+	}
+	if !ast.OutputType().IsExactType(cel.BoolType) {
+		return nil, fmt.Errorf("when expression %q must evaluate to bool, not %s", expr, ast.OutputType())
+	}
+	p, err := env.Program(ast, cel.CostLimit(whenCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid when expression %q: %w", expr, err)
+	}
+	whenPrograms.Store(expr, p)
+	return p, nil
+}
+
+// validateRules compiles the when expressions and parses the commands of the rules and their nested rules.
+func validateRules(rules []LabelerRule) error {
+	var errs []error
+	for _, r := range rules {
+		if r.When != "" {
+			if _, err := compileWhen(r.When); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if r.Command != "" {
+			if _, err := splitCommand(r.Command); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := validateRules(slices.Concat(r.Any, r.All, r.Not, r.None)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateConfig checks the when expressions and commands of every label, so that errors are reported when the config is loaded.
+func validateConfig(cfg LabelerConfig) error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(cfg)) {
+		for _, m := range cfg[name].Matcher {
+			if err := validateRules(slices.Concat(m.Any, m.All)); err != nil {
+				errs = append(errs, fmt.Errorf("label %q: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// evalWhen evaluates the when expression against the PR and its changed files. Errors are logged and fail the condition.
+func evalWhen(expr string, changedFiles []*CommitFile, pr *PullRequest) matchState {
+	p, err := compileWhen(expr)
+	if err != nil {
+		logger.Warn("Skipping invalid when expression", "error", err)
+		return stateFailed
+	}
+	out, _, err := p.Eval(map[string]any{
+		"pr":    newWhenPullRequest(pr),
+		"files": filenames(changedFiles),
+	})
+	if err != nil {
+		logger.Warn("Failed to evaluate when expression", "expression", expr, "pr", pr.GetNumber(), "error", err)
+		return stateFailed
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return stateFailed
+	}
+	return stateOf(matched)
+}
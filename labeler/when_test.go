@@ -0,0 +1,127 @@
+package labeler
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckMatchConfigs_When(t *testing.T) {
+	content := `
+large-api:
+  - when: 'files.any(f, f.startsWith("api/")) && pr.additions > 300'
+draft-docs:
+  - all:
+    - changed-files:
+      - any-glob-to-any-file: 'docs/**'
+    - when: 'pr.draft && !("docs" in pr.labels)'
+hotfix:
+  - when: 'pr.head.startsWith("hotfix/") || pr.title.lowerAscii().contains("hotfix")'
+small:
+  - when: 'pr.additions + pr.deletions < 10 && size(files) <= 2 && files.all(f, !f.startsWith("api/"))'
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(content), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	tests := []struct {
+		name  string
+		pr    *PullRequest
+		files []string
+		want  []string
+	}{
+		{
+			name:  "large api change",
+			pr:    &PullRequest{Title: Ptr("Add endpoint"), Additions: Ptr(400), Deletions: Ptr(3), Head: &PullRequestBranch{Ref: Ptr("feature")}},
+			files: []string{"api/server.go", "docs/api.md"},
+			want:  []string{"large-api"},
+		},
+		{
+			name:  "draft docs hotfix",
+			pr:    &PullRequest{Title: Ptr("HOTFIX typo"), Draft: Ptr(true), Additions: Ptr(1), Deletions: Ptr(1), Head: &PullRequestBranch{Ref: Ptr("fix")}},
+			files: []string{"docs/index.md"},
+			want:  []string{"draft-docs", "hotfix", "small"},
+		},
+		{
+			name:  "draft docs already labeled",
+			pr:    &PullRequest{Draft: Ptr(true), Additions: Ptr(50), Head: &PullRequestBranch{Ref: Ptr("hotfix/docs")}, Labels: []*Label{{Name: Ptr("docs")}}},
+			files: []string{"docs/index.md"},
+			want:  []string{"hotfix"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []*CommitFile
+			for _, f := range tt.files {
+				files = append(files, &CommitFile{Filename: Ptr(f)})
+			}
+			result := NewMatcher(context.TODO(), nil).CheckMatchConfigs(cfg, files, tt.pr)
+			if !slices.Equal(result.Matched, tt.want) {
+				t.Errorf("matched = %v, want %v", result.Matched, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_InvalidWhen(t *testing.T) {
+	tests := []struct {
+		name    string
+		when    string
+		message string
+	}{
+		{name: "syntax error", when: "pr.additions >", message: "Syntax error"},
+		{name: "unknown field", when: "pr.additons > 10", message: "undefined field 'additons'"},
+		{name: "unknown variable", when: "env.HOME == ''", message: "undeclared reference to 'env'"},
+		{name: "type mismatch", when: "pr.title > 10", message: "no matching overload"},
+		{name: "not bool", when: "pr.additions", message: "must evaluate to bool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "bad:\n  - all:\n    - when: '" + strings.ReplaceAll(tt.when, "'", "''") + "'\n"
+			_, err := LoadConfigFromReader(strings.NewReader(content), false)
+			if err == nil {
+				t.Fatalf("expected an error for %q", tt.when)
+			}
+			if !strings.Contains(err.Error(), `label "bad"`) || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("error %q should name the label and contain %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestCheckMatchConfigs_WhenFailure(t *testing.T) {
+	content := `
+negated:
+  - not:
+    - when: 'int(pr.title) > 0'
+none:
+  - none:
+    - when: 'int(pr.title) > 0'
+any:
+  - any:
+    - when: 'int(pr.title) > 0'
+    - base-branch: main
+all:
+  - all:
+    - when: 'int(pr.title) > 0'
+    - base-branch: develop
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(content), true)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	pr := &PullRequest{Title: Ptr("not a number"), Base: &PullRequestBranch{Ref: Ptr("main")}}
+	result, traces := NewMatcher(context.TODO(), nil).ExplainMatchConfigs(cfg, nil, pr)
+	if !slices.Equal(result.Matched, []string{"any"}) || !slices.Equal(result.Unmatched, []string{"all"}) {
+		t.Errorf("matched = %v, unmatched = %v", result.Matched, result.Unmatched)
+	}
+	if !slices.Equal(result.Failed, []string{"negated", "none"}) {
+		t.Errorf("failed = %v, want [negated none]", result.Failed)
+	}
+	for _, tr := range traces {
+		if tr.Failed != slices.Contains(result.Failed, tr.Name) {
+			t.Errorf("trace of %s: failed = %v", tr.Name, tr.Failed)
+		}
+	}
+}
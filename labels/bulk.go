@@ -0,0 +1,98 @@
+package labels
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"golang.org/x/sync/errgroup"
+)
+
+type SyncStatus string
+
+const (
+	SyncStatusApplied   SyncStatus = "applied"
+	SyncStatusPlanned   SyncStatus = "planned"
+	SyncStatusUnchanged SyncStatus = "unchanged"
+	SyncStatusSkipped   SyncStatus = "skipped"
+	SyncStatusFailed    SyncStatus = "failed"
+)
+
+// SyncResult is the outcome of reflecting the source labels to a destination repository.
+type SyncResult struct {
+	Repository string     `json:"repository"`
+	Status     SyncStatus `json:"status"`
+	Plan       *Plan      `json:"plan,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// BulkSync reflects the source labels to many destination repositories concurrently.
+type BulkSync struct {
+	Client      *gh.GitHubClient
+	Source      []*Label
+	Options     SyncOptions
+	Concurrency int
+	DryRun      bool
+	State       *SyncState
+}
+
+// Run syncs every destination and returns the results in the order of the destinations.
+// A failure of one destination does not stop the others.
+func (b *BulkSync) Run(ctx context.Context, destinations []repository.Repository) []*SyncResult {
+	results := make([]*SyncResult, len(destinations))
+	var eg errgroup.Group
+	if b.Concurrency > 0 {
+		eg.SetLimit(b.Concurrency)
+	}
+	var mu sync.Mutex
+	done := 0
+	for i, dst := range destinations {
+		eg.Go(func() error {
+			results[i] = b.sync(ctx, dst)
+			mu.Lock()
+			done++
+			logger.Info("Synced labels", "repository", results[i].Repository, "status", results[i].Status, "progress", done, "total", len(destinations))
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return results
+}
+
+func (b *BulkSync) sync(ctx context.Context, dst repository.Repository) *SyncResult {
+	result := &SyncResult{Repository: parser.GetRepositoryFullName(dst)}
+	if b.State.IsCompleted(result.Repository) {
+		result.Status = SyncStatusSkipped
+		return result
+	}
+	fail := func(err error) *SyncResult {
+		result.Status = SyncStatusFailed
+		result.Error = err.Error()
+		return result
+	}
+	plan, err := ComputeSyncPlan(ctx, b.Client, dst, b.Source, b.Options)
+	if err != nil {
+		return fail(err)
+	}
+	result.Plan = plan
+	switch {
+	case b.DryRun:
+		result.Status = SyncStatusPlanned
+		return result
+	case !plan.HasChanges():
+		result.Status = SyncStatusUnchanged
+	default:
+		if err := plan.Apply(ctx, b.Client); err != nil {
+			return fail(err)
+		}
+		result.Status = SyncStatusApplied
+	}
+	if err := b.State.MarkCompleted(result.Repository); err != nil {
+		logger.Warn("Failed to save state file", "repository", result.Repository, "error", err)
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package labels
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/prompter"
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+// newPrompter returns a prompter on the standard streams. It fails when the terminal is not interactive.
+func newPrompter() (*prompter.Prompter, error) {
+	t := term.FromEnv()
+	if !t.IsTerminalOutput() {
+		return nil, fmt.Errorf("cannot ask for confirmation in non-interactive mode: use --yes to proceed")
+	}
+	return prompter.New(os.Stdin, os.Stdout, os.Stderr), nil
+}
+
+// Confirm asks the user to confirm an operation. It fails when the terminal is not interactive.
+func Confirm(message string) (bool, error) {
+	p, err := newPrompter()
+	if err != nil {
+		return false, err
+	}
+	return p.Confirm(message, false)
+}
+
+// SelectMany asks the user to select any number of the options, all selected by default, and returns the indexes of the selected ones.
+// It fails when the terminal is not interactive.
+func SelectMany(message string, options []string) ([]int, error) {
+	p, err := newPrompter()
+	if err != nil {
+		return nil, err
+	}
+	return p.MultiSelect(message, options, options)
+}
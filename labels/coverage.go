@@ -0,0 +1,88 @@
+package labels
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ddddddO/gtree"
+)
+
+// DeadGlob is a glob in a label's changed-files rules that matches no file.
+type DeadGlob struct {
+	Label   string `json:"label"`
+	Pattern string `json:"pattern"`
+}
+
+// LabelOverlap is a pair of labels whose globs match many of the same files.
+// Ratio is the number of shared files divided by the number of files of the label with fewer files.
+type LabelOverlap struct {
+	Labels []string `json:"labels"`
+	Shared int      `json:"shared"`
+	Ratio  float64  `json:"ratio"`
+}
+
+// CoverageReport is how well the changed-files globs of a labeler config cover the files of a repository.
+// Unmatched lists the files that no label matches, with directories whose files are all unmatched collapsed into
+// the directory path with a trailing slash.
+type CoverageReport struct {
+	Files     int            `json:"files"`
+	Matched   int            `json:"matched"`
+	Unmatched []string       `json:"unmatched"`
+	DeadGlobs []DeadGlob     `json:"deadGlobs"`
+	Overlaps  []LabelOverlap `json:"overlaps"`
+}
+
+// CollapseUnmatched returns the unmatched files, replacing the files of a directory with the directory path
+// (with a trailing slash) if no file under the directory is matched. The result is sorted.
+func CollapseUnmatched(files []string, unmatched map[string]struct{}) []string {
+	// covered is the set of directories with at least one matched file under them.
+	covered := make(map[string]struct{})
+	for _, f := range files {
+		if _, ok := unmatched[f]; ok {
+			continue
+		}
+		for dir := path.Dir(f); dir != "."; dir = path.Dir(dir) {
+			covered[dir] = struct{}{}
+		}
+	}
+	seen := make(map[string]struct{})
+	var result []string
+	for _, f := range files {
+		if _, ok := unmatched[f]; !ok {
+			continue
+		}
+		// Use the outermost directory that has no matched file, or the file itself.
+		entry := f
+		for dir := path.Dir(f); dir != "."; dir = path.Dir(dir) {
+			if _, ok := covered[dir]; ok {
+				break
+			}
+			entry = dir + "/"
+		}
+		if _, ok := seen[entry]; ok {
+			continue
+		}
+		seen[entry] = struct{}{}
+		result = append(result, entry)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// unmatchedTree builds a tree of the unmatched paths.
+func unmatchedTree(paths []string) *gtree.Node {
+	root := gtree.NewRoot(".")
+	for _, p := range paths {
+		dir := strings.HasSuffix(p, "/")
+		segments := strings.Split(strings.TrimSuffix(p, "/"), "/")
+		node := root
+		for i, s := range segments {
+			if i < len(segments)-1 || dir {
+				s += "/"
+			}
+			node = node.Add(s)
+		}
+	}
+	return root
+}
@@ -0,0 +1,49 @@
+package labels
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/ddddddO/gtree"
+)
+
+func TestCollapseUnmatched(t *testing.T) {
+	files := []string{
+		"README.md",
+		"api/server.go",
+		"api/legacy/old.go",
+		"tools/gen/main.go",
+		"tools/gen/tmpl.txt",
+		"tools/lint.sh",
+		"web/app.ts",
+	}
+	unmatched := map[string]struct{}{
+		"README.md":          {},
+		"api/legacy/old.go":  {},
+		"tools/gen/main.go":  {},
+		"tools/gen/tmpl.txt": {},
+		"tools/lint.sh":      {},
+	}
+	got := CollapseUnmatched(files, unmatched)
+	want := []string{"README.md", "api/legacy/", "tools/"}
+	if !slices.Equal(got, want) {
+		t.Errorf("CollapseUnmatched() = %v, want %v", got, want)
+	}
+}
+
+func TestUnmatchedTree(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gtree.OutputFromRoot(&buf, unmatchedTree([]string{"README.md", "api/legacy/", "api/main.go"})); err != nil {
+		t.Fatalf("OutputFromRoot error: %v", err)
+	}
+	want := `.
+├── README.md
+└── api/
+    ├── legacy/
+    └── main.go
+`
+	if buf.String() != want {
+		t.Errorf("unexpected tree:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
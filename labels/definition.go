@@ -0,0 +1,68 @@
+package labels
+
+import (
+	"slices"
+	"strings"
+)
+
+// Definition is the desired state of a repository label.
+// A nil Color or Description means the field is not managed and is left as it is.
+// Usage is informational only (the number of issues and pull requests with the label) and is ignored when applied.
+type Definition struct {
+	Name        string   `json:"name" yaml:"name"`
+	Color       *string  `json:"color,omitempty" yaml:"color,omitempty"`
+	Description *string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Aliases     []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Usage       *int     `json:"usage,omitempty" yaml:"usage,omitempty"`
+}
+
+// NewDefinition creates a Definition from a repository label.
+func NewDefinition(label *Label) Definition {
+	d := Definition{
+		Name:        label.GetName(),
+		Color:       Ptr(NormalizeColor(label.GetColor())),
+		Description: Ptr(label.GetDescription()),
+	}
+	return d
+}
+
+// NewDefinitions creates Definitions from repository labels, sorted by name.
+func NewDefinitions(labels []*Label) []Definition {
+	definitions := make([]Definition, 0, len(labels))
+	for _, l := range labels {
+		if l == nil || l.Name == nil {
+			continue
+		}
+		definitions = append(definitions, NewDefinition(l))
+	}
+	slices.SortFunc(definitions, func(a, b Definition) int {
+		return strings.Compare(NormalizeName(a.Name), NormalizeName(b.Name))
+	})
+	return definitions
+}
+
+// GetColor returns the color of the definition, or an empty string if it is not managed.
+func (d Definition) GetColor() string {
+	if d.Color == nil {
+		return ""
+	}
+	return *d.Color
+}
+
+// GetDescription returns the description of the definition, or an empty string if it is not managed.
+func (d Definition) GetDescription() string {
+	if d.Description == nil {
+		return ""
+	}
+	return *d.Description
+}
+
+// NormalizeColor strips the leading '#' and lowercases the color code.
+func NormalizeColor(color string) string {
+	return strings.ToLower(strings.TrimPrefix(color, "#"))
+}
+
+// NormalizeName returns the key used to compare label names. GitHub label names are case-insensitive.
+func NormalizeName(name string) string {
+	return strings.ToLower(name)
+}
@@ -0,0 +1,193 @@
+package labels
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+type FindingKind string
+
+const (
+	FindingUnused             FindingKind = "unused"
+	FindingDuplicate          FindingKind = "duplicate"
+	FindingMissingDescription FindingKind = "missing-description"
+	FindingPalette            FindingKind = "palette"
+)
+
+// MergeSuggestion suggests merging a label into another with repo rename.
+type MergeSuggestion struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Command returns the repo rename command line that performs the merge.
+func (m MergeSuggestion) Command(repo string) string {
+	return fmt.Sprintf("gh label-kit repo rename --repo %s %q %q", repo, m.From, m.To)
+}
+
+// Finding is a problem found in the labels of a repository.
+type Finding struct {
+	Kind       FindingKind      `json:"kind"`
+	Label      string           `json:"label"`
+	Detail     string           `json:"detail"`
+	Suggestion *MergeSuggestion `json:"suggestion,omitempty"`
+}
+
+// DiagnoseOptions controls the checks of Diagnose.
+type DiagnoseOptions struct {
+	// Usage is the number of issues and pull requests per label name. Unused labels are not checked if nil.
+	Usage map[string]int
+	// Palette is the expected colors. Colors are not checked if nil.
+	Palette *Palette
+}
+
+// duplicateKey returns the key to find near-duplicate labels: the last segment of a prefixed name
+// (e.g. "type: bug" or "kind/bug") in lower case, without whitespace, emoji and punctuation.
+func duplicateKey(name string) string {
+	_, key := duplicatePrefixKey(name)
+	return key
+}
+
+// duplicatePrefixKey returns the prefix of the name (e.g. "type" of "type: bug"), or "" if it has none,
+// and its duplicate key, both normalized the same way.
+func duplicatePrefixKey(name string) (string, string) {
+	prefix := ""
+	if i := strings.LastIndexAny(name, ":/"); i >= 0 && i < len(name)-1 {
+		prefix, name = name[:i], name[i+1:]
+	}
+	return normalizeDuplicateKey(prefix), normalizeDuplicateKey(name)
+}
+
+func normalizeDuplicateKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// duplicateGroups groups the near-duplicate labels. Labels are only duplicates if they have the same key and the same prefix,
+// so that "priority: high" and "impact: high" are kept apart. A label without prefix is a duplicate of the prefixed labels
+// with its key only if they all have the same prefix, since it cannot be merged into several labels.
+func duplicateGroups(repoLabels []*Label) [][]*Label {
+	type prefixGroup struct {
+		prefix string
+		labels []*Label
+	}
+	byKey := make(map[string][]*prefixGroup)
+	var keys []string
+	for _, l := range repoLabels {
+		prefix, key := duplicatePrefixKey(l.GetName())
+		if key == "" {
+			continue
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		i := slices.IndexFunc(byKey[key], func(g *prefixGroup) bool { return g.prefix == prefix })
+		if i < 0 {
+			byKey[key] = append(byKey[key], &prefixGroup{prefix: prefix})
+			i = len(byKey[key]) - 1
+		}
+		byKey[key][i].labels = append(byKey[key][i].labels, l)
+	}
+	var groups [][]*Label
+	for _, key := range keys {
+		prefixGroups := byKey[key]
+		if len(prefixGroups) == 2 && (prefixGroups[0].prefix == "" || prefixGroups[1].prefix == "") {
+			prefixGroups = []*prefixGroup{{labels: slices.Concat(prefixGroups[0].labels, prefixGroups[1].labels)}}
+		}
+		for _, g := range prefixGroups {
+			if len(g.labels) > 1 {
+				groups = append(groups, g.labels)
+			}
+		}
+	}
+	return groups
+}
+
+// Diagnose finds unused, near-duplicate, undescribed and off-palette labels, sorted by label name.
+func Diagnose(repoLabels []*Label, opts DiagnoseOptions) []Finding {
+	var findings []Finding
+	for _, l := range repoLabels {
+		name := l.GetName()
+		if opts.Usage != nil && opts.Usage[name] == 0 {
+			findings = append(findings, Finding{Kind: FindingUnused, Label: name, Detail: "not used by any issue or pull request"})
+		}
+		if strings.TrimSpace(l.GetDescription()) == "" {
+			findings = append(findings, Finding{Kind: FindingMissingDescription, Label: name, Detail: "description is empty"})
+		}
+		if opts.Palette != nil {
+			if detail := opts.Palette.Check(name, l.GetColor()); detail != "" {
+				findings = append(findings, Finding{Kind: FindingPalette, Label: name, Detail: detail})
+			}
+		}
+	}
+	for _, group := range duplicateGroups(repoLabels) {
+		target := mergeTarget(group, opts.Usage)
+		for _, l := range group {
+			if l == target {
+				continue
+			}
+			findings = append(findings, Finding{
+				Kind:       FindingDuplicate,
+				Label:      l.GetName(),
+				Detail:     fmt.Sprintf("near-duplicate of %s", target.GetName()),
+				Suggestion: &MergeSuggestion{From: l.GetName(), To: target.GetName()},
+			})
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Or(strings.Compare(NormalizeName(a.Label), NormalizeName(b.Label)), strings.Compare(string(a.Kind), string(b.Kind)))
+	})
+	return findings
+}
+
+// plainness ranks how plain a label name is: 2 for an undecorated lower-case name, 1 for an undecorated name, 0 otherwise.
+func plainness(name string) int {
+	key := duplicateKey(name)
+	switch {
+	case name == key:
+		return 2
+	case strings.ToLower(name) == key:
+		return 1
+	}
+	return 0
+}
+
+// mergeTarget chooses the label to keep among near-duplicates: the most used one,
+// then the plainest name (see plainness), then the shortest name.
+func mergeTarget(group []*Label, usage map[string]int) *Label {
+	return slices.MinFunc(group, func(a, b *Label) int {
+		if c := cmp.Compare(usage[b.GetName()], usage[a.GetName()]); c != 0 {
+			return c
+		}
+		return cmp.Or(
+			cmp.Compare(plainness(b.GetName()), plainness(a.GetName())),
+			cmp.Compare(len(a.GetName()), len(b.GetName())),
+			strings.Compare(a.GetName(), b.GetName()),
+		)
+	})
+}
+
+// ComputeUsage counts the issues and pull requests of every label.
+func ComputeUsage(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, repoLabels []*Label) (map[string]int, error) {
+	usage := make(map[string]int, len(repoLabels))
+	for _, l := range repoLabels {
+		count, err := CountLabelUsage(ctx, g, repo, l.GetName())
+		if err != nil {
+			return nil, err
+		}
+		usage[l.GetName()] = count
+	}
+	return usage, nil
+}
@@ -0,0 +1,132 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDuplicateKey(t *testing.T) {
+	tests := map[string]string{
+		"bug":        "bug",
+		"Bug":        "bug",
+		"type: bug":  "bug",
+		"kind/bug":   "bug",
+		"🐛 bug":      "bug",
+		" Bug ":      "bug",
+		"good first": "goodfirst",
+		"🚀":          "",
+	}
+	for name, want := range tests {
+		if got := duplicateKey(name); got != want {
+			t.Errorf("duplicateKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func findFinding(findings []Finding, kind FindingKind, label string) *Finding {
+	for i := range findings {
+		if findings[i].Kind == kind && findings[i].Label == label {
+			return &findings[i]
+		}
+	}
+	return nil
+}
+
+func TestDiagnose(t *testing.T) {
+	repoLabels := []*Label{
+		newLabel("bug", "d73a4a", "Something isn't working"),
+		newLabel("Bug", "d73a4a", "Something isn't working"),
+		newLabel("type: bug", "ff0000", ""),
+		newLabel("documentation", "0075ca", "Docs"),
+	}
+	palette := &Palette{Colors: []string{"d73a4a", "0075ca"}}
+	if err := palette.compile(); err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+	usage := map[string]int{"bug": 3, "Bug": 5, "type: bug": 0, "documentation": 1}
+
+	findings := Diagnose(repoLabels, DiagnoseOptions{Usage: usage, Palette: palette})
+
+	if f := findFinding(findings, FindingUnused, "type: bug"); f == nil {
+		t.Error("type: bug should be unused")
+	}
+	if f := findFinding(findings, FindingMissingDescription, "type: bug"); f == nil {
+		t.Error("type: bug should have missing description")
+	}
+	if f := findFinding(findings, FindingPalette, "type: bug"); f == nil || !strings.Contains(f.Detail, "ff0000") {
+		t.Errorf("type: bug should conflict with palette: %v", f)
+	}
+	// Bug is used the most, so the others are merged into it.
+	for _, name := range []string{"bug", "type: bug"} {
+		f := findFinding(findings, FindingDuplicate, name)
+		if f == nil || f.Suggestion == nil || f.Suggestion.To != "Bug" {
+			t.Errorf("%s should be merged into Bug: %v", name, f)
+		}
+	}
+	if f := findFinding(findings, FindingDuplicate, "Bug"); f != nil {
+		t.Errorf("Bug should be the merge target: %v", f)
+	}
+	if f := findFinding(findings, FindingDuplicate, "documentation"); f != nil {
+		t.Errorf("documentation should not be a duplicate: %v", f)
+	}
+}
+
+func TestDiagnose_MergeTargetWithoutUsage(t *testing.T) {
+	findings := Diagnose([]*Label{newLabel("type: bug", "d73a4a", "x"), newLabel("Bug", "d73a4a", "x"), newLabel("bug", "d73a4a", "x")}, DiagnoseOptions{})
+	for _, name := range []string{"Bug", "type: bug"} {
+		f := findFinding(findings, FindingDuplicate, name)
+		if f == nil || f.Suggestion.To != "bug" {
+			t.Errorf("%s should be merged into bug: %v", name, f)
+		}
+	}
+	if f := findFinding(findings, FindingUnused, "bug"); f != nil {
+		t.Error("unused labels should not be reported without usage")
+	}
+}
+
+func TestPalette_Check(t *testing.T) {
+	palette := &Palette{
+		Colors: []string{"#D73A4A"},
+		Rules:  []PaletteRule{{Pattern: "^area/", Color: "0e8a16"}},
+	}
+	if err := palette.compile(); err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+	if d := palette.Check("bug", "d73a4a"); d != "" {
+		t.Errorf("bug should match the palette: %s", d)
+	}
+	if d := palette.Check("area/ui", "0E8A16"); d != "" {
+		t.Errorf("area/ui should match the rule: %s", d)
+	}
+	if d := palette.Check("area/api", "d73a4a"); d == "" {
+		t.Error("area/api should conflict with the rule")
+	}
+	if d := palette.Check("question", "d876e3"); d == "" {
+		t.Error("question should not be in the palette")
+	}
+}
+
+func TestDiagnose_DuplicatesKeepPrefixes(t *testing.T) {
+	findings := Diagnose([]*Label{
+		newLabel("priority: high", "d73a4a", "x"),
+		newLabel("impact: high", "d73a4a", "x"),
+		newLabel("high", "d73a4a", "x"),
+		newLabel("area/api", "0e8a16", "x"),
+		newLabel("team/api", "0e8a16", "x"),
+		newLabel("Area: API", "0e8a16", "x"),
+		newLabel("area/web", "0e8a16", "x"),
+		newLabel("web", "0e8a16", "x"),
+	}, DiagnoseOptions{})
+	for _, name := range []string{"priority: high", "impact: high", "high", "team/api"} {
+		if f := findFinding(findings, FindingDuplicate, name); f != nil {
+			t.Errorf("%s should not be a duplicate: %v", name, f)
+		}
+	}
+	// Labels with the same prefix, or a bare name with a single prefix, are still duplicates
+	if f := findFinding(findings, FindingDuplicate, "Area: API"); f == nil || f.Suggestion.To != "area/api" {
+		t.Errorf("Area: API should be merged into area/api: %v", f)
+	}
+	if f := findFinding(findings, FindingDuplicate, "area/web"); f == nil || f.Suggestion.To != "web" {
+		t.Errorf("area/web should be merged into web: %v", f)
+	}
+}
@@ -0,0 +1,58 @@
+package labels
+
+import (
+	"github.com/ddddddO/gtree"
+	"github.com/fatih/color"
+)
+
+// MatchTrace is the evaluation of a labeler condition: a label, a combinator such as any or all, or a single condition.
+// Failed is set if the condition could not be evaluated, in which case it is neither matched nor unmatched.
+// Children are the conditions evaluated to decide the result, so conditions after the deciding one are not listed.
+type MatchTrace struct {
+	Name     string        `json:"name"`
+	Matched  bool          `json:"matched"`
+	Failed   bool          `json:"failed,omitempty"`
+	Children []*MatchTrace `json:"children,omitempty"`
+}
+
+// traceText returns the name of the trace prefixed with a mark of its result.
+func (r *Renderer) traceText(t *MatchTrace) string {
+	if t.Failed {
+		if r.Color {
+			return color.YellowString("!") + " " + t.Name
+		}
+		return "! " + t.Name
+	}
+	if t.Matched {
+		if r.Color {
+			return color.GreenString("✓") + " " + t.Name
+		}
+		return "✓ " + t.Name
+	}
+	if r.Color {
+		return color.RedString("✗") + " " + t.Name
+	}
+	return "✗ " + t.Name
+}
+
+// addTrace adds the children of the trace to the node recursively.
+func (r *Renderer) addTrace(node *gtree.Node, t *MatchTrace) {
+	for _, c := range t.Children {
+		r.addTrace(node.Add(r.traceText(c)), c)
+	}
+}
+
+// RenderMatchTraces renders the evaluation of each label as a tree, or exports the traces if an exporter is set.
+func (r *Renderer) RenderMatchTraces(traces []*MatchTrace) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(traces)
+	}
+	for _, t := range traces {
+		root := gtree.NewRoot(r.traceText(t), gtree.WithDuplicationAllowed())
+		r.addTrace(root, t)
+		if err := gtree.OutputFromRoot(r.IO.Out, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
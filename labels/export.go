@@ -0,0 +1,126 @@
+package labels
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ExportFormatYAML string = "yaml"
+	ExportFormatJSON string = "json"
+	ExportFormatCSV  string = "csv"
+)
+
+var ExportFormats = []string{
+	ExportFormatYAML,
+	ExportFormatJSON,
+	ExportFormatCSV,
+}
+
+var csvHeader = []string{"name", "color", "description", "aliases", "usage"}
+
+// aliasSeparator separates aliases in a CSV cell.
+const aliasSeparator = ";"
+
+// Export writes the manifest in the given format (yaml, json or csv).
+func (m *Manifest) Export(w io.Writer, format string) error {
+	switch format {
+	case ExportFormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(m); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case ExportFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(m)
+	case ExportFormatCSV:
+		return m.exportCSV(w)
+	}
+	return fmt.Errorf("unsupported export format: %s", format)
+}
+
+func (m *Manifest) exportCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, d := range m.Labels {
+		usage := ""
+		if d.Usage != nil {
+			usage = strconv.Itoa(*d.Usage)
+		}
+		record := []string{d.Name, d.GetColor(), d.GetDescription(), strings.Join(d.Aliases, aliasSeparator), usage}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// LoadManifestFromCSV loads a manifest in CSV format with a header row.
+// The name column is required. Empty color cells are not managed, while an empty description cell clears the description
+// like `description: ""` in YAML, so that an exported manifest applies the same way in every format.
+// Descriptions are not managed if there is no description column.
+func LoadManifestFromCSV(r io.Reader) (*Manifest, error) {
+	reader := csv.NewReader(r)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if len(records) == 0 {
+		return &Manifest{}, nil
+	}
+	columns := make(map[string]int)
+	for i, h := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("failed to parse manifest: name column is required")
+	}
+	cell := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	m := &Manifest{Labels: []Definition{}}
+	for _, record := range records[1:] {
+		d := Definition{Name: cell(record, "name")}
+		if color := cell(record, "color"); color != "" {
+			d.Color = Ptr(color)
+		}
+		if _, ok := columns["description"]; ok {
+			d.Description = Ptr(cell(record, "description"))
+		}
+		if aliases := cell(record, "aliases"); aliases != "" {
+			for _, alias := range strings.Split(aliases, aliasSeparator) {
+				if alias = strings.TrimSpace(alias); alias != "" {
+					d.Aliases = append(d.Aliases, alias)
+				}
+			}
+		}
+		if usage := cell(record, "usage"); usage != "" {
+			n, err := strconv.Atoi(usage)
+			if err != nil {
+				return nil, fmt.Errorf("invalid usage for label %s: %s", d.Name, usage)
+			}
+			d.Usage = Ptr(n)
+		}
+		m.Labels = append(m.Labels, d)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
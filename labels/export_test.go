@@ -0,0 +1,130 @@
+package labels
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func exportTestManifest() *Manifest {
+	return &Manifest{Labels: []Definition{
+		{Name: "bug", Color: Ptr("d73a4a"), Description: Ptr("Something isn't working"), Aliases: []string{"Bug", "type: bug"}, Usage: Ptr(3)},
+		{Name: "question", Color: Ptr("d876e3"), Description: Ptr("Further information, is requested")},
+	}}
+}
+
+func TestManifestExport_RoundTrip(t *testing.T) {
+	for _, format := range []string{ExportFormatYAML, ExportFormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exportTestManifest().Export(&buf, format); err != nil {
+				t.Fatalf("Export error: %v", err)
+			}
+			m, err := LoadManifestFromReader(&buf)
+			if err != nil {
+				t.Fatalf("LoadManifestFromReader error: %v", err)
+			}
+			assertExportedManifest(t, m)
+		})
+	}
+}
+
+func TestManifestExport_CSVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestManifest().Export(&buf, ExportFormatCSV); err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "name,color,description,aliases,usage\n") {
+		t.Errorf("unexpected CSV header: %q", buf.String())
+	}
+	m, err := LoadManifestFromCSV(&buf)
+	if err != nil {
+		t.Fatalf("LoadManifestFromCSV error: %v", err)
+	}
+	assertExportedManifest(t, m)
+}
+
+func TestManifestExport_UnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestManifest().Export(&buf, "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestLoadManifestFromCSV(t *testing.T) {
+	content := "Name,Color\nbug,\nenhancement,a2eeef\n"
+	m, err := LoadManifestFromCSV(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("LoadManifestFromCSV error: %v", err)
+	}
+	if len(m.Labels) != 2 {
+		t.Fatalf("expected 2 labels, got %d", len(m.Labels))
+	}
+	if m.Labels[0].Color != nil || m.Labels[0].Description != nil {
+		t.Errorf("bug should not manage color and description: %+v", m.Labels[0])
+	}
+
+	cleared, err := LoadManifestFromCSV(strings.NewReader("name,description\nbug,\n"))
+	if err != nil {
+		t.Fatalf("LoadManifestFromCSV error: %v", err)
+	}
+	if d := cleared.Labels[0].Description; d == nil || *d != "" {
+		t.Errorf("an empty description cell should clear the description: %v", d)
+	}
+	if m.Labels[1].GetColor() != "a2eeef" {
+		t.Errorf("unexpected enhancement color: %s", m.Labels[1].GetColor())
+	}
+
+	if _, err := LoadManifestFromCSV(strings.NewReader("color\nd73a4a\n")); err == nil {
+		t.Error("expected error for missing name column")
+	}
+	if _, err := LoadManifestFromCSV(strings.NewReader("name,usage\nbug,many\n")); err == nil {
+		t.Error("expected error for invalid usage")
+	}
+}
+
+func assertExportedManifest(t *testing.T, m *Manifest) {
+	t.Helper()
+	if len(m.Labels) != 2 {
+		t.Fatalf("expected 2 labels, got %d", len(m.Labels))
+	}
+	bug := m.Labels[0]
+	if bug.Name != "bug" || bug.GetColor() != "d73a4a" || bug.GetDescription() != "Something isn't working" {
+		t.Errorf("unexpected bug label: %+v", bug)
+	}
+	if len(bug.Aliases) != 2 || bug.Aliases[1] != "type: bug" {
+		t.Errorf("unexpected aliases: %v", bug.Aliases)
+	}
+	if bug.Usage == nil || *bug.Usage != 3 {
+		t.Errorf("unexpected usage: %v", bug.Usage)
+	}
+	question := m.Labels[1]
+	if question.GetDescription() != "Further information, is requested" || question.Usage != nil {
+		t.Errorf("unexpected question label: %+v", question)
+	}
+}
+
+func TestManifestExport_EmptyDescriptionRoundTrip(t *testing.T) {
+	exported := &Manifest{Labels: []Definition{{Name: "wontfix", Color: Ptr("ffffff"), Description: Ptr("")}}}
+	for _, format := range ExportFormats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exported.Export(&buf, format); err != nil {
+				t.Fatalf("Export error: %v", err)
+			}
+			var m *Manifest
+			var err error
+			if format == ExportFormatCSV {
+				m, err = LoadManifestFromCSV(&buf)
+			} else {
+				m, err = LoadManifestFromReader(&buf)
+			}
+			if err != nil {
+				t.Fatalf("load error: %v", err)
+			}
+			if len(m.Labels) != 1 || m.Labels[0].Description == nil || *m.Labels[0].Description != "" {
+				t.Errorf("an empty description should be cleared after the round trip: %+v", m.Labels)
+			}
+		})
+	}
+}
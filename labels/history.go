@@ -0,0 +1,124 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v84/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+type Timeline = github.Timeline
+
+// LabelAction is whether a label was added to or removed from an issue.
+type LabelAction string
+
+const (
+	LabelActionAdded   LabelAction = "added"
+	LabelActionRemoved LabelAction = "removed"
+)
+
+// LabelEvent is a label added to or removed from an issue or pull request.
+type LabelEvent struct {
+	Time       time.Time   `json:"time"`
+	Action     LabelAction `json:"action"`
+	Label      string      `json:"label"`
+	Color      string      `json:"color"`
+	Actor      string      `json:"actor"`
+	Automation bool        `json:"automation"`
+}
+
+// AutomationMatcher decides whether an actor is an automation account.
+// Bot accounts are always automation; other accounts match if their login matches any of the patterns.
+type AutomationMatcher struct {
+	patterns []*regexp.Regexp
+}
+
+// NewAutomationMatcher compiles the login patterns of the automation accounts. Patterns are anchored and case-insensitive.
+func NewAutomationMatcher(patterns []string) (*AutomationMatcher, error) {
+	m := &AutomationMatcher{}
+	for _, p := range patterns {
+		re, err := regexp.Compile(`(?i)^(?:` + p + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid automation pattern '%s': %w", p, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// Match returns whether the user is an automation account.
+func (m *AutomationMatcher) Match(user *github.User) bool {
+	if user == nil {
+		return false
+	}
+	login := user.GetLogin()
+	if user.GetType() == "Bot" || strings.HasSuffix(login, "[bot]") {
+		return true
+	}
+	if m == nil {
+		return false
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(login) {
+			return true
+		}
+	}
+	return false
+}
+
+// newLabelEvents extracts the labeled and unlabeled events from the timeline in chronological order.
+func newLabelEvents(timeline []*Timeline, automation *AutomationMatcher) []LabelEvent {
+	var events []LabelEvent
+	for _, t := range timeline {
+		var action LabelAction
+		switch t.GetEvent() {
+		case "labeled":
+			action = LabelActionAdded
+		case "unlabeled":
+			action = LabelActionRemoved
+		default:
+			continue
+		}
+		label := t.GetLabel()
+		events = append(events, LabelEvent{
+			Time:       t.GetCreatedAt().Time,
+			Action:     action,
+			Label:      label.GetName(),
+			Color:      NormalizeColor(label.GetColor()),
+			Actor:      t.GetActor().GetLogin(),
+			Automation: automation.Match(t.Actor),
+		})
+	}
+	return events
+}
+
+// ListLabelHistory reconstructs when each label was added to or removed from the issue or pull request, and by whom.
+func ListLabelHistory(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, number int, automation *AutomationMatcher) ([]LabelEvent, error) {
+	var timeline []*Timeline
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		var page []*Timeline
+		var resp *github.Response
+		err := retryOnRateLimit(ctx, func() error {
+			var err error
+			page, resp, err = g.GetClient().Issues.ListIssueTimeline(ctx, repo.Owner, repo.Name, number, opts)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list timeline of #%d: %w", number, err)
+		}
+		timeline = append(timeline, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	logger.Debug("Listed issue timeline", "number", number, "events", len(timeline))
+	return newLabelEvents(timeline, automation), nil
+}
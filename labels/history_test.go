@@ -0,0 +1,56 @@
+package labels
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v84/github"
+)
+
+func TestAutomationMatcher(t *testing.T) {
+	m, err := NewAutomationMatcher([]string{"release-bot", "ci-.*"})
+	if err != nil {
+		t.Fatalf("NewAutomationMatcher error: %v", err)
+	}
+	tests := map[string]struct {
+		user *github.User
+		want bool
+	}{
+		"bot type":       {&github.User{Login: Ptr("renovate"), Type: Ptr("Bot")}, true},
+		"bot suffix":     {&github.User{Login: Ptr("github-actions[bot]")}, true},
+		"pattern":        {&github.User{Login: Ptr("Release-Bot")}, true},
+		"pattern prefix": {&github.User{Login: Ptr("ci-runner")}, true},
+		"anchored":       {&github.User{Login: Ptr("my-release-bot")}, false},
+		"human":          {&github.User{Login: Ptr("octocat"), Type: Ptr("User")}, false},
+		"no actor":       {nil, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := m.Match(tt.user); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := NewAutomationMatcher([]string{"("}); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+}
+
+func TestNewLabelEvents(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	timeline := []*Timeline{
+		{Event: Ptr("labeled"), Label: &github.Label{Name: Ptr("bug"), Color: Ptr("D73A4A")}, Actor: &github.User{Login: Ptr("github-actions[bot]")}, CreatedAt: &github.Timestamp{Time: at}},
+		{Event: Ptr("commented"), Actor: &github.User{Login: Ptr("octocat")}},
+		{Event: Ptr("unlabeled"), Label: &github.Label{Name: Ptr("bug")}, Actor: &github.User{Login: Ptr("octocat")}, CreatedAt: &github.Timestamp{Time: at.Add(time.Hour)}},
+	}
+	events := newLabelEvents(timeline, nil)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	if e := events[0]; e.Action != LabelActionAdded || e.Label != "bug" || e.Color != "d73a4a" || e.Actor != "github-actions[bot]" || !e.Automation || !e.Time.Equal(at) {
+		t.Errorf("unexpected first event: %+v", e)
+	}
+	if e := events[1]; e.Action != LabelActionRemoved || e.Actor != "octocat" || e.Automation {
+		t.Errorf("unexpected second event: %+v", e)
+	}
+}
@@ -0,0 +1,77 @@
+package labels
+
+import (
+	"slices"
+	"strings"
+)
+
+// ImpactCase is the labels of a pull request before and after a change.
+type ImpactCase struct {
+	Number int
+	Before []string
+	After  []string
+}
+
+// LabelImpact is the number of pull requests with a label before and after a change, and the pull requests that gain or lose it.
+type LabelImpact struct {
+	Name   string `json:"name"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+	Gained []int  `json:"gained"`
+	Lost   []int  `json:"lost"`
+}
+
+// HasChange returns whether any pull request gains or loses the label.
+func (i *LabelImpact) HasChange() bool {
+	return len(i.Gained) > 0 || len(i.Lost) > 0
+}
+
+// ComputeImpact counts, per label, the pull requests with the label before and after a change and the pull requests that gain or lose it.
+// Only the given label names are reported, sorted by name. Label names are compared ignoring case.
+func ComputeImpact(names []string, cases []ImpactCase) []*LabelImpact {
+	impacts := make(map[string]*LabelImpact, len(names))
+	for _, name := range names {
+		key := NormalizeName(name)
+		if _, ok := impacts[key]; !ok {
+			impacts[key] = &LabelImpact{Name: name, Gained: []int{}, Lost: []int{}}
+		}
+	}
+	for _, c := range cases {
+		before := normalizedSet(c.Before)
+		after := normalizedSet(c.After)
+		for key, impact := range impacts {
+			_, had := before[key]
+			_, has := after[key]
+			if had {
+				impact.Before++
+			}
+			if has {
+				impact.After++
+			}
+			switch {
+			case has && !had:
+				impact.Gained = append(impact.Gained, c.Number)
+			case had && !has:
+				impact.Lost = append(impact.Lost, c.Number)
+			}
+		}
+	}
+	result := make([]*LabelImpact, 0, len(impacts))
+	for _, impact := range impacts {
+		slices.Sort(impact.Gained)
+		slices.Sort(impact.Lost)
+		result = append(result, impact)
+	}
+	slices.SortFunc(result, func(a, b *LabelImpact) int {
+		return strings.Compare(NormalizeName(a.Name), NormalizeName(b.Name))
+	})
+	return result
+}
+
+func normalizedSet(names []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[NormalizeName(name)] = struct{}{}
+	}
+	return set
+}
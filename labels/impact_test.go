@@ -0,0 +1,28 @@
+package labels
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestComputeImpact(t *testing.T) {
+	cases := []ImpactCase{
+		{Number: 1, Before: []string{"docs"}, After: []string{"Docs", "backend"}},
+		{Number: 2, Before: []string{"backend", "manual"}, After: nil},
+		{Number: 3, Before: []string{"backend"}, After: []string{"backend"}},
+	}
+	impacts := ComputeImpact([]string{"docs", "backend", "Backend", "unused"}, cases)
+	if len(impacts) != 3 {
+		t.Fatalf("expected 3 labels, got %d", len(impacts))
+	}
+	backend, docs, unused := impacts[0], impacts[1], impacts[2]
+	if backend.Name != "backend" || backend.Before != 2 || backend.After != 2 || !slices.Equal(backend.Gained, []int{1}) || !slices.Equal(backend.Lost, []int{2}) {
+		t.Errorf("unexpected impact: %+v", backend)
+	}
+	if docs.Before != 1 || docs.After != 1 || docs.HasChange() {
+		t.Errorf("label names should be compared ignoring case: %+v", docs)
+	}
+	if unused.Before != 0 || unused.After != 0 || unused.Gained == nil || unused.Lost == nil {
+		t.Errorf("unexpected impact: %+v", unused)
+	}
+}
@@ -0,0 +1,219 @@
+package labels
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+type ItemKind string
+
+const (
+	ItemKindIssue      ItemKind = "issue"
+	ItemKindDiscussion ItemKind = "discussion"
+)
+
+type LabelOperation string
+
+const (
+	LabelOperationAdd    LabelOperation = "add"
+	LabelOperationRemove LabelOperation = "remove"
+	LabelOperationSet    LabelOperation = "set"
+)
+
+type ItemStatus string
+
+const (
+	ItemStatusUpdated ItemStatus = "updated"
+	ItemStatusPlanned ItemStatus = "planned"
+	ItemStatusFailed  ItemStatus = "failed"
+)
+
+// ItemTarget is an issue, pull request or discussion to edit the labels of.
+type ItemTarget struct {
+	Repository repository.Repository
+	Number     int
+}
+
+func (t ItemTarget) String() string {
+	return fmt.Sprintf("%s#%d", parser.GetRepositoryFullName(t.Repository), t.Number)
+}
+
+// ParseItemTarget parses a number (e.g. 12 or #12) or URL of an issue, pull request or discussion.
+// Numbers refer to the repository given by repo (or the current repository if empty), while URLs carry their own repository.
+func ParseItemTarget(kind ItemKind, input, repo string) (ItemTarget, error) {
+	opt := parser.RepositoryInput(repo)
+	if strings.Contains(input, "://") {
+		opt = parser.RepositoryFromURL(input)
+	}
+	repository, err := parser.Repository(opt)
+	if err != nil {
+		return ItemTarget{}, fmt.Errorf("failed to resolve repository of %s: %w", input, err)
+	}
+	var number int
+	switch kind {
+	case ItemKindDiscussion:
+		number, err = parser.GetDiscussionNumberFromString(input)
+	default:
+		number, err = parser.GetIssueNumberFromString(input)
+		if err != nil {
+			number, err = parser.GetPullRequestNumberFromString(input)
+		}
+	}
+	if err != nil {
+		return ItemTarget{}, err
+	}
+	return ItemTarget{Repository: repository, Number: number}, nil
+}
+
+// ParseItemTargets parses the targets. An input "-" is replaced by the whitespace-separated targets read from stdin.
+// All targets must be on the same host so that they are edited with one client.
+func ParseItemTargets(kind ItemKind, inputs []string, repo string, stdin io.Reader) ([]ItemTarget, error) {
+	var targets []ItemTarget
+	for _, input := range inputs {
+		if input == "-" {
+			scanner := bufio.NewScanner(stdin)
+			scanner.Split(bufio.ScanWords)
+			for scanner.Scan() {
+				target, err := ParseItemTarget(kind, scanner.Text(), repo)
+				if err != nil {
+					return nil, err
+				}
+				targets = append(targets, target)
+			}
+			if err := scanner.Err(); err != nil {
+				return nil, fmt.Errorf("failed to read targets from stdin: %w", err)
+			}
+			continue
+		}
+		target, err := ParseItemTarget(kind, input, repo)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	for _, t := range targets[min(1, len(targets)):] {
+		if t.Repository.Host != targets[0].Repository.Host {
+			return nil, fmt.Errorf("targets must be on the same host: %s vs %s", targets[0].Repository.Host, t.Repository.Host)
+		}
+	}
+	return targets, nil
+}
+
+// ItemResult is the outcome of editing the labels of a target.
+type ItemResult struct {
+	Target string     `json:"target"`
+	Number int        `json:"number"`
+	Status ItemStatus `json:"status"`
+	Labels []string   `json:"labels"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// ItemLabelEditor applies a label operation to many issues or discussions.
+type ItemLabelEditor struct {
+	Kind      ItemKind
+	Operation LabelOperation
+	Labels    []string
+	DryRun    bool
+}
+
+// Run edits the targets one by one, retrying on rate limits. A failure of one target does not stop the others.
+func (e *ItemLabelEditor) Run(ctx context.Context, g *gh.GitHubClient, targets []ItemTarget) []*ItemResult {
+	results := make([]*ItemResult, 0, len(targets))
+	for _, target := range targets {
+		result := &ItemResult{Target: target.String(), Number: target.Number, Labels: []string{}}
+		results = append(results, result)
+		if e.DryRun {
+			result.Status = ItemStatusPlanned
+			continue
+		}
+		var labels []*Label
+		err := retryOnRateLimit(ctx, func() error {
+			var err error
+			labels, err = e.edit(ctx, g, target)
+			return err
+		})
+		if err != nil {
+			result.Status = ItemStatusFailed
+			result.Error = err.Error()
+			continue
+		}
+		result.Status = ItemStatusUpdated
+		for _, l := range labels {
+			result.Labels = append(result.Labels, l.GetName())
+		}
+		logger.Debug("Edited labels", "kind", e.Kind, "target", result.Target, "operation", e.Operation)
+	}
+	return results
+}
+
+func (e *ItemLabelEditor) edit(ctx context.Context, g *gh.GitHubClient, target ItemTarget) ([]*Label, error) {
+	repo := target.Repository
+	switch e.Kind {
+	case ItemKindIssue:
+		switch e.Operation {
+		case LabelOperationAdd:
+			return gh.AddIssueLabels(ctx, g, repo, target.Number, e.Labels)
+		case LabelOperationRemove:
+			return gh.RemoveIssueLabels(ctx, g, repo, target.Number, e.Labels)
+		case LabelOperationSet:
+			return gh.SetIssueLabels(ctx, g, repo, target.Number, e.Labels)
+		}
+	case ItemKindDiscussion:
+		switch e.Operation {
+		case LabelOperationAdd:
+			return gh.AddDiscussionLabels(ctx, g, repo, target.Number, e.Labels)
+		case LabelOperationRemove:
+			return gh.RemoveDiscussionLabels(ctx, g, repo, target.Number, e.Labels)
+		case LabelOperationSet:
+			return gh.SetDiscussionLabels(ctx, g, repo, target.Number, e.Labels)
+		}
+	}
+	return nil, fmt.Errorf("unsupported operation: %s %s", e.Kind, e.Operation)
+}
+
+// SearchItemTargets returns the issues (or pull requests) or discussions of the repository matching the query.
+// All pages are fetched, but GitHub returns at most 1,000 results for a search, which is warned about.
+func SearchItemTargets(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, kind ItemKind, query string) ([]ItemTarget, error) {
+	var targets []ItemTarget
+	switch kind {
+	case ItemKindIssue:
+		issues, err := gh.SearchIssues(ctx, g, repo, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			targets = append(targets, ItemTarget{Repository: repo, Number: issue.GetNumber()})
+		}
+	case ItemKindDiscussion:
+		discussions, err := gh.SearchDiscussions(ctx, g, repo, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range discussions {
+			targets = append(targets, ItemTarget{Repository: repo, Number: int(d.Number)})
+		}
+	}
+	if len(targets) >= searchResultLimit {
+		logger.Warn("The search returned the maximum number of results, items beyond them are not included; narrow the query and run again", "query", query, "count", len(targets))
+	}
+	return targets, nil
+}
+
+// CountFailed returns the number of failed results.
+func CountFailed(results []*ItemResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.Status == ItemStatusFailed {
+			failed++
+		}
+	}
+	return failed
+}
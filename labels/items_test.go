@@ -0,0 +1,52 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseItemTargets(t *testing.T) {
+	stdin := strings.NewReader("4\n#5 https://github.com/other/repo/pull/6\n")
+	targets, err := ParseItemTargets(ItemKindIssue, []string{"1", "#2", "https://github.com/owner/repo/issues/3", "-"}, "owner/repo", stdin)
+	if err != nil {
+		t.Fatalf("ParseItemTargets error: %v", err)
+	}
+	want := []string{"owner/repo#1", "owner/repo#2", "owner/repo#3", "owner/repo#4", "owner/repo#5", "other/repo#6"}
+	if len(targets) != len(want) {
+		t.Fatalf("expected %d targets, got %v", len(want), targets)
+	}
+	for i, target := range targets {
+		if target.String() != want[i] {
+			t.Errorf("targets[%d] = %s, want %s", i, target, want[i])
+		}
+	}
+}
+
+func TestParseItemTarget_PullRequestURL(t *testing.T) {
+	target, err := ParseItemTarget(ItemKindIssue, "https://github.com/other/repo/pull/12", "owner/repo")
+	if err != nil {
+		t.Fatalf("ParseItemTarget error: %v", err)
+	}
+	if target.String() != "other/repo#12" {
+		t.Errorf("target = %s, want other/repo#12", target)
+	}
+}
+
+func TestParseItemTargets_Discussion(t *testing.T) {
+	targets, err := ParseItemTargets(ItemKindDiscussion, []string{"https://github.com/owner/repo/discussions/7", "8"}, "owner/repo", nil)
+	if err != nil {
+		t.Fatalf("ParseItemTargets error: %v", err)
+	}
+	if len(targets) != 2 || targets[0].Number != 7 || targets[1].Number != 8 {
+		t.Errorf("unexpected targets: %v", targets)
+	}
+}
+
+func TestParseItemTargets_Invalid(t *testing.T) {
+	if _, err := ParseItemTargets(ItemKindIssue, []string{"abc"}, "owner/repo", nil); err == nil {
+		t.Error("expected error for invalid target")
+	}
+	if _, err := ParseItemTargets(ItemKindIssue, []string{"1", "https://ghes.example.com/owner/repo/issues/2"}, "owner/repo", nil); err == nil {
+		t.Error("expected error for targets on different hosts")
+	}
+}
@@ -0,0 +1,86 @@
+package labels
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+var colorPattern = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
+
+// Manifest is a declarative list of repository labels.
+type Manifest struct {
+	Labels []Definition `json:"labels" yaml:"labels"`
+}
+
+// LoadManifestFromReader loads a manifest in YAML (or JSON) format.
+func LoadManifestFromReader(r io.Reader) (*Manifest, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	logger.Debug("Manifest loaded successfully", "labels", len(m.Labels))
+	return &m, nil
+}
+
+// LoadManifest loads a manifest from a local file. Files with the .csv extension are loaded as CSV.
+func LoadManifest(path string) (*Manifest, error) {
+	logger.Debug("Loading manifest from local file", "path", path)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return LoadManifestFromCSV(f)
+	}
+	return LoadManifestFromReader(f)
+}
+
+// Validate checks that label names are unique, aliases do not collide with names, and colors are valid.
+func (m *Manifest) Validate() error {
+	names := make(map[string]string, len(m.Labels))
+	for _, d := range m.Labels {
+		if d.Name == "" {
+			return fmt.Errorf("label name must not be empty")
+		}
+		key := NormalizeName(d.Name)
+		if _, ok := names[key]; ok {
+			return fmt.Errorf("duplicate label: %s", d.Name)
+		}
+		names[key] = d.Name
+		if d.Color != nil && !colorPattern.MatchString(*d.Color) {
+			return fmt.Errorf("invalid color for label %s: %s", d.Name, *d.Color)
+		}
+	}
+	aliases := make(map[string]string)
+	for _, d := range m.Labels {
+		for _, alias := range d.Aliases {
+			key := NormalizeName(alias)
+			if name, ok := names[key]; ok && name != d.Name {
+				return fmt.Errorf("alias %s of label %s collides with label %s", alias, d.Name, name)
+			}
+			if owner, ok := aliases[key]; ok && owner != d.Name {
+				return fmt.Errorf("alias %s is used by both %s and %s", alias, owner, d.Name)
+			}
+			aliases[key] = d.Name
+		}
+	}
+	return nil
+}
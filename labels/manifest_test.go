@@ -0,0 +1,91 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadManifestFromReader_YAML(t *testing.T) {
+	content := `
+labels:
+  - name: bug
+    color: "#d73a4a"
+    description: Something isn't working
+    aliases:
+      - Bug
+      - "type: bug"
+  - name: documentation
+`
+	m, err := LoadManifestFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("LoadManifestFromReader error: %v", err)
+	}
+	if len(m.Labels) != 2 {
+		t.Fatalf("expected 2 labels, got %d", len(m.Labels))
+	}
+	bug := m.Labels[0]
+	if bug.Name != "bug" || bug.GetColor() != "#d73a4a" || bug.GetDescription() != "Something isn't working" {
+		t.Errorf("unexpected bug label: %+v", bug)
+	}
+	if len(bug.Aliases) != 2 {
+		t.Errorf("expected 2 aliases, got %v", bug.Aliases)
+	}
+	if m.Labels[1].Color != nil || m.Labels[1].Description != nil {
+		t.Errorf("documentation should not manage color and description: %+v", m.Labels[1])
+	}
+}
+
+func TestLoadManifestFromReader_JSON(t *testing.T) {
+	content := `{"labels": [{"name": "bug", "color": "d73a4a", "description": ""}]}`
+	m, err := LoadManifestFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("LoadManifestFromReader error: %v", err)
+	}
+	if len(m.Labels) != 1 || m.Labels[0].Description == nil || *m.Labels[0].Description != "" {
+		t.Errorf("empty description should be managed: %+v", m.Labels)
+	}
+}
+
+func TestLoadManifestFromReader_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field": `
+labels:
+  - name: bug
+    colour: d73a4a
+`,
+		"empty name": `
+labels:
+  - color: d73a4a
+`,
+		"duplicate": `
+labels:
+  - name: bug
+  - name: Bug
+`,
+		"invalid color": `
+labels:
+  - name: bug
+    color: red
+`,
+		"alias collides with name": `
+labels:
+  - name: bug
+  - name: defect
+    aliases: [bug]
+`,
+		"alias used twice": `
+labels:
+  - name: bug
+    aliases: [defect]
+  - name: issue
+    aliases: [Defect]
+`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadManifestFromReader(strings.NewReader(content)); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
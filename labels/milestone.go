@@ -0,0 +1,160 @@
+package labels
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v84/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// MilestoneItem is an issue or pull request in a milestone.
+type MilestoneItem struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	State       string `json:"state"`
+	PullRequest bool   `json:"pullRequest"`
+	URL         string `json:"url"`
+}
+
+// MilestoneLabelSummary is the number of issues and pull requests with a label in a milestone.
+// Group is the prefix of the label name when grouping by prefix, or empty.
+type MilestoneLabelSummary struct {
+	Group              string          `json:"group"`
+	Name               string          `json:"name"`
+	Color              string          `json:"color"`
+	OpenIssues         int             `json:"openIssues"`
+	ClosedIssues       int             `json:"closedIssues"`
+	OpenPullRequests   int             `json:"openPullRequests"`
+	ClosedPullRequests int             `json:"closedPullRequests"`
+	Total              int             `json:"total"`
+	Items              []MilestoneItem `json:"items,omitempty"`
+}
+
+// MilestoneSummary is the per-label summary of a milestone. Labels are sorted by group and name.
+type MilestoneSummary struct {
+	Number int                      `json:"number"`
+	Title  string                   `json:"title"`
+	State  string                   `json:"state"`
+	Labels []*MilestoneLabelSummary `json:"labels"`
+}
+
+// labelGroup returns the part of the label name before the separator, or an empty string if there is none.
+func labelGroup(name, separator string) string {
+	if separator == "" {
+		return ""
+	}
+	group, _, found := strings.Cut(name, separator)
+	if !found {
+		return ""
+	}
+	return group
+}
+
+// summarizeLabels counts the issues and pull requests per label. Labels are grouped by the prefix before
+// the separator if it is not empty, and the items are listed per label if withItems is true.
+func summarizeLabels(issues []*Issue, separator string, withItems bool) []*MilestoneLabelSummary {
+	summaries := make(map[string]*MilestoneLabelSummary)
+	for _, issue := range issues {
+		item := MilestoneItem{
+			Number:      issue.GetNumber(),
+			Title:       issue.GetTitle(),
+			State:       issue.GetState(),
+			PullRequest: issue.IsPullRequest(),
+			URL:         issue.GetHTMLURL(),
+		}
+		for _, label := range issue.Labels {
+			key := NormalizeName(label.GetName())
+			s, ok := summaries[key]
+			if !ok {
+				s = &MilestoneLabelSummary{
+					Group: labelGroup(label.GetName(), separator),
+					Name:  label.GetName(),
+					Color: NormalizeColor(label.GetColor()),
+				}
+				summaries[key] = s
+			}
+			open := item.State == "open"
+			switch {
+			case item.PullRequest && open:
+				s.OpenPullRequests++
+			case item.PullRequest:
+				s.ClosedPullRequests++
+			case open:
+				s.OpenIssues++
+			default:
+				s.ClosedIssues++
+			}
+			s.Total++
+			if withItems {
+				s.Items = append(s.Items, item)
+			}
+		}
+	}
+	result := make([]*MilestoneLabelSummary, 0, len(summaries))
+	for _, s := range summaries {
+		slices.SortFunc(s.Items, func(a, b MilestoneItem) int {
+			return cmp.Compare(a.Number, b.Number)
+		})
+		result = append(result, s)
+	}
+	slices.SortFunc(result, func(a, b *MilestoneLabelSummary) int {
+		return cmp.Or(
+			strings.Compare(NormalizeName(a.Group), NormalizeName(b.Group)),
+			strings.Compare(NormalizeName(a.Name), NormalizeName(b.Name)),
+		)
+	})
+	return result
+}
+
+// ListMilestoneIssues lists all open and closed issues and pull requests in the milestone.
+func ListMilestoneIssues(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, number int) ([]*Issue, error) {
+	var issues []*Issue
+	opts := &github.IssueListByRepoOptions{
+		Milestone:   fmt.Sprintf("%d", number),
+		State:       "all",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		var page []*Issue
+		var resp *github.Response
+		err := retryOnRateLimit(ctx, func() error {
+			var err error
+			page, resp, err = g.GetClient().Issues.ListByRepo(ctx, repo.Owner, repo.Name, opts)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues in milestone %d: %w", number, err)
+		}
+		issues = append(issues, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.ListOptions.Page = resp.NextPage
+	}
+	logger.Debug("Listed milestone issues", "milestone", number, "issues", len(issues))
+	return issues, nil
+}
+
+// SummarizeMilestone counts the issues and pull requests per label in the milestone.
+func SummarizeMilestone(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, milestone any, separator string, withItems bool) (*MilestoneSummary, error) {
+	m, err := gh.GetMilestone(ctx, g, repo, milestone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get milestone: %w", err)
+	}
+	issues, err := ListMilestoneIssues(ctx, g, repo, m.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+	return &MilestoneSummary{
+		Number: m.GetNumber(),
+		Title:  m.GetTitle(),
+		State:  m.GetState(),
+		Labels: summarizeLabels(issues, separator, withItems),
+	}, nil
+}
@@ -0,0 +1,44 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v84/github"
+)
+
+func TestSummarizeLabels(t *testing.T) {
+	issues := []*Issue{
+		{Number: Ptr(3), State: Ptr("open"), Labels: []*Label{{Name: Ptr("area/api"), Color: Ptr("#0075CA")}, {Name: Ptr("bug")}}},
+		{Number: Ptr(1), State: Ptr("closed"), Labels: []*Label{{Name: Ptr("area/api")}}},
+		{Number: Ptr(2), State: Ptr("open"), PullRequestLinks: &github.PullRequestLinks{}, Labels: []*Label{{Name: Ptr("Area/API")}, {Name: Ptr("area/web")}}},
+		{Number: Ptr(4), State: Ptr("closed"), PullRequestLinks: &github.PullRequestLinks{}},
+	}
+
+	summaries := summarizeLabels(issues, "/", true)
+	if len(summaries) != 3 {
+		t.Fatalf("expected 3 labels, got %d", len(summaries))
+	}
+	if summaries[0].Name != "bug" || summaries[0].Group != "" {
+		t.Errorf("ungrouped labels should come first: %+v", summaries[0])
+	}
+	api := summaries[1]
+	if api.Name != "area/api" || api.Group != "area" || api.Color != "0075ca" {
+		t.Errorf("unexpected label: %+v", api)
+	}
+	if api.OpenIssues != 1 || api.ClosedIssues != 1 || api.OpenPullRequests != 1 || api.ClosedPullRequests != 0 || api.Total != 3 {
+		t.Errorf("unexpected counts: %+v", api)
+	}
+	if len(api.Items) != 3 || api.Items[0].Number != 1 || api.Items[2].Number != 3 || !api.Items[1].PullRequest {
+		t.Errorf("items should be sorted by number: %+v", api.Items)
+	}
+	if summaries[2].Name != "area/web" {
+		t.Errorf("unexpected label: %+v", summaries[2])
+	}
+
+	summaries = summarizeLabels(issues, "", false)
+	for _, s := range summaries {
+		if s.Group != "" || len(s.Items) != 0 {
+			t.Errorf("unexpected group or items: %+v", s)
+		}
+	}
+}
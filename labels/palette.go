@@ -0,0 +1,80 @@
+package labels
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PaletteRule requires labels whose name matches the pattern to have the color.
+type PaletteRule struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Color   string `json:"color" yaml:"color"`
+
+	re *regexp.Regexp
+}
+
+// Palette is the set of colors that repository labels are expected to use.
+type Palette struct {
+	// Colors lists the allowed colors. Any color is allowed if empty.
+	Colors []string `json:"colors,omitempty" yaml:"colors,omitempty"`
+	// Rules requires specific colors for labels by name. The first matching rule applies.
+	Rules []PaletteRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// LoadPalette loads a palette from a YAML or JSON file.
+func LoadPalette(path string) (*Palette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &Palette{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse palette: %w", err)
+	}
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Palette) compile() error {
+	for i, c := range p.Colors {
+		if !colorPattern.MatchString(c) {
+			return fmt.Errorf("invalid palette color: %s", c)
+		}
+		p.Colors[i] = NormalizeColor(c)
+	}
+	for i := range p.Rules {
+		re, err := regexp.Compile(p.Rules[i].Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid palette pattern %s: %w", p.Rules[i].Pattern, err)
+		}
+		if !colorPattern.MatchString(p.Rules[i].Color) {
+			return fmt.Errorf("invalid palette color: %s", p.Rules[i].Color)
+		}
+		p.Rules[i].re = re
+		p.Rules[i].Color = NormalizeColor(p.Rules[i].Color)
+	}
+	return nil
+}
+
+// Check returns why the label color conflicts with the palette, or an empty string if it does not.
+func (p *Palette) Check(name, color string) string {
+	color = NormalizeColor(color)
+	for _, rule := range p.Rules {
+		if rule.re != nil && rule.re.MatchString(name) {
+			if rule.Color != color {
+				return fmt.Sprintf("color %s should be %s (rule %s)", color, rule.Color, rule.Pattern)
+			}
+			return ""
+		}
+	}
+	if len(p.Colors) > 0 && !slices.Contains(p.Colors, color) {
+		return fmt.Sprintf("color %s is not in the palette", color)
+	}
+	return ""
+}
@@ -0,0 +1,200 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+type ChangeType string
+
+const (
+	ChangeCreate ChangeType = "create"
+	ChangeUpdate ChangeType = "update"
+	ChangeRename ChangeType = "rename"
+	ChangeDelete ChangeType = "delete"
+)
+
+// Change is a single operation on a repository label.
+// Before is the current state (nil for create) and After is the desired state (nil for delete).
+type Change struct {
+	Type   ChangeType  `json:"type"`
+	Name   string      `json:"name"`
+	Before *Definition `json:"before,omitempty"`
+	After  *Definition `json:"after,omitempty"`
+}
+
+// NewName returns the name of the label after the change is applied.
+func (c Change) NewName() string {
+	if c.After != nil {
+		return c.After.Name
+	}
+	return c.Name
+}
+
+// DiffOptions controls which kinds of changes Diff produces.
+type DiffOptions struct {
+	// Update updates the color and description of existing labels that differ from the definition.
+	Update bool
+	// Prune deletes existing labels that are not in the definitions.
+	Prune bool
+}
+
+// Diff computes the changes required to turn the current labels into the desired definitions.
+// Labels are matched by name case-insensitively, then by aliases; a label matched by alias is renamed.
+func Diff(current []*Label, desired []Definition, opts DiffOptions) []Change {
+	currentMap := make(map[string]*Label, len(current))
+	for _, l := range current {
+		if l == nil || l.Name == nil {
+			continue
+		}
+		currentMap[NormalizeName(l.GetName())] = l
+	}
+	claimed := make(map[string]struct{}, len(desired))
+	for _, d := range desired {
+		if _, ok := currentMap[NormalizeName(d.Name)]; ok {
+			claimed[NormalizeName(d.Name)] = struct{}{}
+		}
+	}
+
+	changes := []Change{}
+	for _, d := range desired {
+		after := d
+		after.Aliases = nil
+		after.Usage = nil
+		l, ok := currentMap[NormalizeName(d.Name)]
+		renamed := false
+		if !ok {
+			for _, alias := range d.Aliases {
+				key := NormalizeName(alias)
+				if _, used := claimed[key]; used {
+					continue
+				}
+				if a, found := currentMap[key]; found {
+					l = a
+					renamed = true
+					claimed[key] = struct{}{}
+					break
+				}
+			}
+		}
+		if l == nil {
+			changes = append(changes, Change{Type: ChangeCreate, Name: d.Name, After: &after})
+			continue
+		}
+
+		before := NewDefinition(l)
+		colorChanged := d.Color != nil && NormalizeColor(*d.Color) != before.GetColor()
+		descriptionChanged := d.Description != nil && *d.Description != before.GetDescription()
+		if colorChanged {
+			after.Color = Ptr(NormalizeColor(*d.Color))
+		} else {
+			after.Color = before.Color
+		}
+		if !descriptionChanged {
+			after.Description = before.Description
+		}
+		switch {
+		case renamed || (opts.Update && l.GetName() != d.Name):
+			changes = append(changes, Change{Type: ChangeRename, Name: l.GetName(), Before: &before, After: &after})
+		case opts.Update && (colorChanged || descriptionChanged):
+			changes = append(changes, Change{Type: ChangeUpdate, Name: l.GetName(), Before: &before, After: &after})
+		}
+	}
+
+	if opts.Prune {
+		for _, l := range current {
+			if l == nil || l.Name == nil {
+				continue
+			}
+			if _, ok := claimed[NormalizeName(l.GetName())]; ok {
+				continue
+			}
+			before := NewDefinition(l)
+			changes = append(changes, Change{Type: ChangeDelete, Name: l.GetName(), Before: &before})
+		}
+	}
+	return changes
+}
+
+// Plan is the list of changes for a repository.
+type Plan struct {
+	Repository string                `json:"repository"`
+	Changes    []Change              `json:"changes"`
+	repo       repository.Repository `json:"-"`
+}
+
+// NewPlan creates a Plan for the repository.
+func NewPlan(repo repository.Repository, changes []Change) *Plan {
+	return &Plan{
+		Repository: parser.GetRepositoryFullName(repo),
+		Changes:    changes,
+		repo:       repo,
+	}
+}
+
+// Repo returns the repository of the plan.
+func (p *Plan) Repo() repository.Repository {
+	return p.repo
+}
+
+// HasChanges returns whether the plan has any changes.
+func (p *Plan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// Count returns the number of changes of the given type.
+func (p *Plan) Count(t ChangeType) int {
+	count := 0
+	for _, c := range p.Changes {
+		if c.Type == t {
+			count++
+		}
+	}
+	return count
+}
+
+// ComputePlan lists the labels of the repository and computes the changes to the desired definitions.
+func ComputePlan(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, desired []Definition, opts DiffOptions) (*Plan, error) {
+	current, err := gh.ListLabels(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+	changes := Diff(current, desired, opts)
+	logger.Debug("Computed label plan", "repository", parser.GetRepositoryFullName(repo), "changes", len(changes))
+	return NewPlan(repo, changes), nil
+}
+
+// Apply applies the changes of the plan to the repository.
+func (p *Plan) Apply(ctx context.Context, g *gh.GitHubClient) error {
+	for _, c := range p.Changes {
+		if err := applyChange(ctx, g, p.repo, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyChange(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, c Change) error {
+	logger.Debug("Applying label change", "repository", parser.GetRepositoryFullName(repo), "type", c.Type, "name", c.Name, "newName", c.NewName())
+	switch c.Type {
+	case ChangeCreate:
+		_, err := gh.CreateLabel(ctx, g, repo, &c.After.Name, c.After.Description, c.After.Color)
+		return err
+	case ChangeUpdate, ChangeRename:
+		label := &Label{
+			Name:        &c.After.Name,
+			Color:       c.After.Color,
+			Description: c.After.Description,
+		}
+		_, err := gh.EditLabel(ctx, g, repo, c.Name, label)
+		return err
+	case ChangeDelete:
+		return gh.DeleteLabel(ctx, g, repo, c.Name)
+	}
+	return fmt.Errorf("unknown change type: %s", c.Type)
+}
@@ -0,0 +1,145 @@
+package labels
+
+import (
+	"testing"
+)
+
+func newLabel(name, color, description string) *Label {
+	return &Label{Name: Ptr(name), Color: Ptr(color), Description: Ptr(description)}
+}
+
+func findChange(changes []Change, name string) *Change {
+	for i := range changes {
+		if changes[i].Name == name {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestNormalizeColor(t *testing.T) {
+	tests := []struct {
+		color string
+		want  string
+	}{
+		{color: "#d73a4a", want: "d73a4a"},
+		{color: "d73a4a", want: "d73a4a"},
+		{color: "#D73A4A", want: "d73a4a"},
+		{color: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeColor(tt.color); got != tt.want {
+			t.Errorf("NormalizeColor(%q) = %q, want %q", tt.color, got, tt.want)
+		}
+	}
+}
+
+func TestDiff_CreateAndUpdate(t *testing.T) {
+	current := []*Label{
+		newLabel("bug", "ffffff", "Something isn't working"),
+		newLabel("docs", "0075ca", "Documentation"),
+	}
+	desired := []Definition{
+		{Name: "bug", Color: Ptr("#D73A4A")},
+		{Name: "docs", Color: Ptr("0075CA"), Description: Ptr("Documentation")},
+		{Name: "feature", Color: Ptr("a2eeef"), Description: Ptr("New feature")},
+	}
+
+	changes := Diff(current, desired, DiffOptions{Update: true})
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	bug := findChange(changes, "bug")
+	if bug == nil || bug.Type != ChangeUpdate {
+		t.Fatalf("bug should be updated, got %+v", bug)
+	}
+	if bug.After.GetColor() != "d73a4a" {
+		t.Errorf("bug color = %q, want %q", bug.After.GetColor(), "d73a4a")
+	}
+	if bug.After.GetDescription() != "Something isn't working" {
+		t.Errorf("unmanaged description should be kept, got %q", bug.After.GetDescription())
+	}
+	feature := findChange(changes, "feature")
+	if feature == nil || feature.Type != ChangeCreate {
+		t.Fatalf("feature should be created, got %+v", feature)
+	}
+
+	changes = Diff(current, desired, DiffOptions{})
+	if len(changes) != 1 || changes[0].Type != ChangeCreate {
+		t.Errorf("only create is expected without Update, got %+v", changes)
+	}
+}
+
+func TestDiff_Prune(t *testing.T) {
+	current := []*Label{
+		newLabel("bug", "d73a4a", ""),
+		newLabel("wontfix", "ffffff", ""),
+	}
+	desired := []Definition{{Name: "Bug"}}
+
+	changes := Diff(current, desired, DiffOptions{Prune: true})
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Type != ChangeDelete || changes[0].Name != "wontfix" {
+		t.Errorf("wontfix should be deleted, got %+v", changes[0])
+	}
+}
+
+func TestDiff_CaseRename(t *testing.T) {
+	current := []*Label{newLabel("Bug", "d73a4a", "")}
+	desired := []Definition{{Name: "bug"}}
+
+	changes := Diff(current, desired, DiffOptions{Update: true})
+	if len(changes) != 1 || changes[0].Type != ChangeRename {
+		t.Fatalf("expected a rename, got %+v", changes)
+	}
+	if changes[0].Name != "Bug" || changes[0].NewName() != "bug" {
+		t.Errorf("rename = %s -> %s, want Bug -> bug", changes[0].Name, changes[0].NewName())
+	}
+
+	if changes := Diff(current, desired, DiffOptions{}); len(changes) != 0 {
+		t.Errorf("case-only difference should be ignored without Update, got %+v", changes)
+	}
+}
+
+func TestDiff_AliasRename(t *testing.T) {
+	current := []*Label{
+		newLabel("type: bug", "ffffff", "old"),
+		newLabel("Defect", "ffffff", ""),
+	}
+	desired := []Definition{
+		{Name: "bug", Color: Ptr("d73a4a"), Aliases: []string{"defect", "type: bug"}},
+	}
+
+	changes := Diff(current, desired, DiffOptions{Update: true, Prune: true})
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	rename := changes[0]
+	if rename.Type != ChangeRename || rename.Name != "Defect" || rename.NewName() != "bug" {
+		t.Errorf("Defect should be renamed to bug, got %+v", rename)
+	}
+	if rename.After.GetColor() != "d73a4a" {
+		t.Errorf("renamed label color = %q, want %q", rename.After.GetColor(), "d73a4a")
+	}
+	if len(rename.After.Aliases) != 0 {
+		t.Errorf("aliases should not be carried to the change: %v", rename.After.Aliases)
+	}
+	if changes[1].Type != ChangeDelete || changes[1].Name != "type: bug" {
+		t.Errorf("the remaining alias label should be pruned, got %+v", changes[1])
+	}
+}
+
+func TestDiff_AliasIgnoredWhenTargetExists(t *testing.T) {
+	current := []*Label{
+		newLabel("bug", "d73a4a", ""),
+		newLabel("defect", "ffffff", ""),
+	}
+	desired := []Definition{{Name: "bug", Aliases: []string{"defect"}}}
+
+	changes := Diff(current, desired, DiffOptions{Update: true})
+	if len(changes) != 0 {
+		t.Errorf("no changes expected when the target label exists, got %+v", changes)
+	}
+}
@@ -0,0 +1,20 @@
+package labels
+
+import "strings"
+
+// BuildSearchQuery builds a search query from free-form terms and label and owner qualifiers.
+// The repository qualifier is added by the search functions of gh.
+func BuildSearchQuery(terms []string, labelNames []string, owner string) (string, error) {
+	query := strings.Join(terms, " ")
+	for _, label := range labelNames {
+		qualifier, err := LabelQualifier(label)
+		if err != nil {
+			return "", err
+		}
+		query += " " + qualifier
+	}
+	if owner != "" {
+		query += " org:" + owner
+	}
+	return query, nil
+}
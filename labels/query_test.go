@@ -0,0 +1,44 @@
+package labels
+
+import "testing"
+
+func TestBuildSearchQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		terms  []string
+		labels []string
+		owner  string
+		want   string
+	}{
+		{name: "terms only", terms: []string{"is:open", "sort:updated"}, want: "is:open sort:updated"},
+		{name: "labels", terms: []string{"is:open"}, labels: []string{"bug", "good first issue"}, want: `is:open label:"bug" label:"good first issue"`},
+		{name: "owner", owner: "org", want: " org:org"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildSearchQuery(tt.terms, tt.labels, tt.owner)
+			if err != nil || got != tt.want {
+				t.Errorf("BuildSearchQuery() = (%q, %v), want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelQualifier(t *testing.T) {
+	tests := map[string]string{
+		"bug":              `label:"bug"`,
+		"good first issue": `label:"good first issue"`,
+	}
+	for name, want := range tests {
+		if got, err := LabelQualifier(name); err != nil || got != want {
+			t.Errorf("LabelQualifier(%q) = (%s, %v), want %s", name, got, err, want)
+		}
+	}
+	// A double quote cannot be escaped in a search qualifier
+	if got, err := LabelQualifier(`say "hi"`); err == nil {
+		t.Errorf("LabelQualifier() = %s, want an error", got)
+	}
+	if _, err := BuildSearchQuery(nil, []string{"bug", `say "hi"`}, ""); err == nil {
+		t.Error("BuildSearchQuery() should fail for a label with a double quote")
+	}
+}
@@ -0,0 +1,294 @@
+package labels
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v84/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultReleaseConfigPath is the default path of the release notes config file, shared with GitHub's automatically generated release notes.
+var DefaultReleaseConfigPath = ".github/release.yml"
+
+// otherChangesTitle is the title of the section for pull requests that match no category.
+const otherChangesTitle = "Other Changes"
+
+// ReleaseExclude excludes pull requests with any of the labels or by any of the authors.
+type ReleaseExclude struct {
+	Labels  []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Authors []string `json:"authors,omitempty" yaml:"authors,omitempty"`
+}
+
+// ReleaseCategory is a section of the release notes. A pull request belongs to the category if it has any of the labels.
+// The label "*" matches every pull request.
+type ReleaseCategory struct {
+	Title   string         `json:"title" yaml:"title"`
+	Labels  []string       `json:"labels" yaml:"labels"`
+	Exclude ReleaseExclude `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+}
+
+// ReleaseConfig maps labels to the sections of the release notes.
+// The format is the same as the config of GitHub's automatically generated release notes.
+type ReleaseConfig struct {
+	Changelog struct {
+		Exclude    ReleaseExclude    `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+		Categories []ReleaseCategory `json:"categories,omitempty" yaml:"categories,omitempty"`
+	} `json:"changelog" yaml:"changelog"`
+}
+
+// ReleaseNoteEntry is a merged pull request in the release notes.
+type ReleaseNoteEntry struct {
+	Number   int       `json:"number"`
+	Title    string    `json:"title"`
+	Author   string    `json:"author"`
+	URL      string    `json:"url"`
+	Labels   []string  `json:"labels"`
+	MergedAt time.Time `json:"mergedAt"`
+}
+
+// ReleaseNoteSection is a section of the release notes.
+type ReleaseNoteSection struct {
+	Title   string             `json:"title"`
+	Entries []ReleaseNoteEntry `json:"entries"`
+}
+
+// ReleaseNotes are the merged pull requests grouped into sections by label.
+type ReleaseNotes struct {
+	Sections []ReleaseNoteSection `json:"sections"`
+}
+
+// LoadReleaseConfigFromReader loads a release notes config in YAML (or JSON) format.
+func LoadReleaseConfigFromReader(r io.Reader) (*ReleaseConfig, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var cfg ReleaseConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse release config: %w", err)
+	}
+	for _, c := range cfg.Changelog.Categories {
+		if c.Title == "" {
+			return nil, fmt.Errorf("category title must not be empty")
+		}
+	}
+	logger.Debug("Release config loaded successfully", "categories", len(cfg.Changelog.Categories))
+	return &cfg, nil
+}
+
+// LoadReleaseConfig loads the release notes config from a local file if it exists, otherwise from the repository at the ref.
+// If the config does not exist in the repository either, an empty config is returned and every pull request is listed in one section.
+func LoadReleaseConfig(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, path string, ref string) (*ReleaseConfig, error) {
+	if f, err := os.Open(path); err == nil {
+		defer f.Close() // nolint
+		logger.Debug("Loading release config from local file", "path", path)
+		return LoadReleaseConfigFromReader(f)
+	}
+	fileContent, err := gh.GetRepositoryFileContent(ctx, g, repo, path, &ref)
+	if err != nil {
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+			logger.Info("Release config not found, listing all pull requests in one section", "path", path)
+			return &ReleaseConfig{}, nil
+		}
+		return nil, err
+	}
+	content, err := fileContent.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode release config: %w", err)
+	}
+	return LoadReleaseConfigFromReader(strings.NewReader(content))
+}
+
+// containsName returns whether the names contain the name, ignoring case.
+func containsName(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(n string) bool {
+		return NormalizeName(n) == NormalizeName(name)
+	})
+}
+
+// excludes returns whether the entry is excluded by its labels or author. Bot authors match with or without the "[bot]" suffix.
+func (e ReleaseExclude) excludes(entry ReleaseNoteEntry) bool {
+	if containsName(e.Authors, entry.Author) || containsName(e.Authors, strings.TrimSuffix(entry.Author, "[bot]")) {
+		return true
+	}
+	return slices.ContainsFunc(entry.Labels, func(l string) bool {
+		return containsName(e.Labels, l)
+	})
+}
+
+// matches returns whether the entry belongs to the category.
+func (c ReleaseCategory) matches(entry ReleaseNoteEntry) bool {
+	if c.Exclude.excludes(entry) {
+		return false
+	}
+	if slices.Contains(c.Labels, "*") {
+		return true
+	}
+	return slices.ContainsFunc(entry.Labels, func(l string) bool {
+		return containsName(c.Labels, l)
+	})
+}
+
+// Group groups the entries into sections in the order of the categories. Each entry belongs to the first matching category,
+// and entries that match no category are listed in the "Other Changes" section. Entries are sorted by merge time.
+// Without categories, all entries are listed in a single section with an empty title.
+func (cfg *ReleaseConfig) Group(entries []ReleaseNoteEntry) *ReleaseNotes {
+	entries = slices.Clone(entries)
+	slices.SortStableFunc(entries, func(a, b ReleaseNoteEntry) int {
+		return a.MergedAt.Compare(b.MergedAt)
+	})
+	categories := cfg.Changelog.Categories
+	sections := make([]ReleaseNoteSection, len(categories))
+	for i, c := range categories {
+		sections[i].Title = c.Title
+	}
+	other := ReleaseNoteSection{Title: otherChangesTitle}
+	if len(categories) == 0 {
+		other.Title = ""
+	}
+	for _, entry := range entries {
+		if cfg.Changelog.Exclude.excludes(entry) {
+			logger.Debug("Excluding pull request from release notes", "number", entry.Number)
+			continue
+		}
+		i := slices.IndexFunc(categories, func(c ReleaseCategory) bool {
+			return c.matches(entry)
+		})
+		if i < 0 {
+			other.Entries = append(other.Entries, entry)
+		} else {
+			sections[i].Entries = append(sections[i].Entries, entry)
+		}
+	}
+	notes := &ReleaseNotes{Sections: []ReleaseNoteSection{}}
+	for _, s := range append(sections, other) {
+		if len(s.Entries) > 0 {
+			notes.Sections = append(notes.Sections, s)
+		}
+	}
+	return notes
+}
+
+// Markdown renders the release notes as Markdown in the style of GitHub's automatically generated release notes.
+func (n *ReleaseNotes) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## What's Changed\n")
+	for _, s := range n.Sections {
+		if s.Title != "" {
+			fmt.Fprintf(&b, "\n### %s\n\n", s.Title)
+		} else {
+			b.WriteString("\n")
+		}
+		for _, e := range s.Entries {
+			fmt.Fprintf(&b, "* %s by @%s in %s\n", e.Title, e.Author, e.URL)
+		}
+	}
+	return b.String()
+}
+
+// labelNames returns the names of the labels. It never returns nil so that the JSON output is stable.
+func labelNames(labels []*Label) []string {
+	names := []string{}
+	for _, l := range labels {
+		names = append(names, l.GetName())
+	}
+	return names
+}
+
+// ListMergedPullRequestsBetween lists the pull requests merged into the commits between the two refs.
+func ListMergedPullRequestsBetween(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, from, to string) ([]ReleaseNoteEntry, error) {
+	var commits []*github.RepositoryCommit
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		var comparison *github.CommitsComparison
+		var resp *github.Response
+		err := retryOnRateLimit(ctx, func() error {
+			var err error
+			comparison, resp, err = g.GetClient().Repositories.CompareCommits(ctx, repo.Owner, repo.Name, from, to, opts)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare %s...%s: %w", from, to, err)
+		}
+		commits = append(commits, comparison.Commits...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	logger.Debug("Listed commits between refs", "from", from, "to", to, "commits", len(commits))
+
+	var entries []ReleaseNoteEntry
+	seen := make(map[int]struct{})
+	for _, commit := range commits {
+		var prs []*PullRequest
+		err := retryOnRateLimit(ctx, func() error {
+			var err error
+			prs, _, err = g.GetClient().PullRequests.ListPullRequestsWithCommit(ctx, repo.Owner, repo.Name, commit.GetSHA(), nil)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests for commit %s: %w", commit.GetSHA(), err)
+		}
+		for _, pr := range prs {
+			if pr.MergedAt == nil {
+				continue
+			}
+			if _, ok := seen[pr.GetNumber()]; ok {
+				continue
+			}
+			seen[pr.GetNumber()] = struct{}{}
+			entries = append(entries, ReleaseNoteEntry{
+				Number:   pr.GetNumber(),
+				Title:    pr.GetTitle(),
+				Author:   pr.GetUser().GetLogin(),
+				URL:      pr.GetHTMLURL(),
+				Labels:   labelNames(pr.Labels),
+				MergedAt: pr.GetMergedAt().Time,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// ListMergedPullRequestsInMilestone lists the merged pull requests in the milestone.
+func ListMergedPullRequestsInMilestone(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, milestone any) ([]ReleaseNoteEntry, error) {
+	m, err := gh.GetMilestone(ctx, g, repo, milestone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get milestone: %w", err)
+	}
+	issues, err := ListMilestoneIssues(ctx, g, repo, m.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+	var entries []ReleaseNoteEntry
+	for _, issue := range issues {
+		if !issue.IsPullRequest() || issue.GetPullRequestLinks().GetMergedAt().IsZero() {
+			continue
+		}
+		entries = append(entries, ReleaseNoteEntry{
+			Number:   issue.GetNumber(),
+			Title:    issue.GetTitle(),
+			Author:   issue.GetUser().GetLogin(),
+			URL:      issue.GetHTMLURL(),
+			Labels:   labelNames(issue.Labels),
+			MergedAt: issue.GetPullRequestLinks().GetMergedAt().Time,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,92 @@
+package labels
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadReleaseConfigFromReader(t *testing.T) {
+	content := `
+changelog:
+  exclude:
+    labels:
+      - skip-changelog
+    authors:
+      - dependabot
+  categories:
+    - title: Features
+      labels:
+        - "type:feature"
+    - title: Bug Fixes
+      labels:
+        - "type:bug"
+      exclude:
+        labels:
+          - wontfix
+`
+	cfg, err := LoadReleaseConfigFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("LoadReleaseConfigFromReader error: %v", err)
+	}
+	if len(cfg.Changelog.Categories) != 2 || cfg.Changelog.Categories[1].Exclude.Labels[0] != "wontfix" {
+		t.Errorf("unexpected categories: %+v", cfg.Changelog.Categories)
+	}
+
+	if _, err := LoadReleaseConfigFromReader(strings.NewReader("changelog:\n  categories:\n    - labels: [bug]\n")); err == nil {
+		t.Errorf("expected error for category without title")
+	}
+	if _, err := LoadReleaseConfigFromReader(strings.NewReader("changelog:\n  sections: []\n")); err == nil {
+		t.Errorf("expected error for unknown field")
+	}
+}
+
+func TestReleaseConfig_Group(t *testing.T) {
+	cfg := &ReleaseConfig{}
+	cfg.Changelog.Exclude = ReleaseExclude{Labels: []string{"skip-changelog"}, Authors: []string{"dependabot"}}
+	cfg.Changelog.Categories = []ReleaseCategory{
+		{Title: "Features", Labels: []string{"type:feature"}},
+		{Title: "Bug Fixes", Labels: []string{"type:bug"}, Exclude: ReleaseExclude{Labels: []string{"wontfix"}}},
+		{Title: "Empty", Labels: []string{"type:none"}},
+	}
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []ReleaseNoteEntry{
+		{Number: 4, Labels: []string{"Type:Feature", "type:bug"}, MergedAt: at.Add(2 * time.Hour)},
+		{Number: 1, Labels: []string{"type:feature"}, MergedAt: at.Add(time.Hour)},
+		{Number: 2, Labels: []string{"type:bug", "wontfix"}, MergedAt: at},
+		{Number: 3, Labels: []string{"type:bug", "skip-changelog"}, MergedAt: at},
+		{Number: 5, Author: "dependabot[bot]", Labels: []string{}, MergedAt: at},
+		{Number: 6, Labels: []string{"type:bug"}, MergedAt: at},
+	}
+	notes := cfg.Group(entries)
+	got := map[string][]int{}
+	var titles []string
+	for _, s := range notes.Sections {
+		titles = append(titles, s.Title)
+		for _, e := range s.Entries {
+			got[s.Title] = append(got[s.Title], e.Number)
+		}
+	}
+	if strings.Join(titles, ",") != "Features,Bug Fixes,Other Changes" {
+		t.Errorf("unexpected sections: %v", titles)
+	}
+	if !slices.Equal(got["Features"], []int{1, 4}) || !slices.Equal(got["Bug Fixes"], []int{6}) || !slices.Equal(got["Other Changes"], []int{2}) {
+		t.Errorf("unexpected grouping: %v", got)
+	}
+
+	notes = (&ReleaseConfig{}).Group(entries[:2])
+	if len(notes.Sections) != 1 || notes.Sections[0].Title != "" || len(notes.Sections[0].Entries) != 2 {
+		t.Errorf("without categories all entries should be in one section: %+v", notes.Sections)
+	}
+}
+
+func TestReleaseNotes_Markdown(t *testing.T) {
+	notes := &ReleaseNotes{Sections: []ReleaseNoteSection{
+		{Title: "Features", Entries: []ReleaseNoteEntry{{Number: 1, Title: "Add foo", Author: "octocat", URL: "https://github.com/o/r/pull/1"}}},
+	}}
+	want := "## What's Changed\n\n### Features\n\n* Add foo by @octocat in https://github.com/o/r/pull/1\n"
+	if got := notes.Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,207 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+type RenameAction string
+
+const (
+	// RenameActionRename renames the label in place, which keeps it on every issue, pull request and discussion.
+	RenameActionRename RenameAction = "rename"
+	// RenameActionMerge moves the old label to the existing new label on every item and deletes the old label.
+	RenameActionMerge RenameAction = "merge"
+	// RenameActionNone means the old label no longer exists, e.g. a previous run has completed.
+	RenameActionNone RenameAction = "none"
+)
+
+// RenamePlan describes how a label is renamed and how many items carry the old label.
+type RenamePlan struct {
+	Repository  string       `json:"repository"`
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	Action      RenameAction `json:"action"`
+	Issues      int          `json:"issues"`
+	Discussions int          `json:"discussions"`
+	repo        repository.Repository
+}
+
+// renameAction decides whether the label is renamed or merged into an existing label.
+// It returns the actual name of the old label, which may differ in case from the given name.
+func renameAction(current []*Label, from, to string) (RenameAction, string, error) {
+	var fromLabel, toLabel *Label
+	for _, l := range current {
+		switch NormalizeName(l.GetName()) {
+		case NormalizeName(from):
+			fromLabel = l
+		case NormalizeName(to):
+			toLabel = l
+		}
+	}
+	if fromLabel == nil {
+		if toLabel != nil || NormalizeName(from) == NormalizeName(to) {
+			return RenameActionNone, from, nil
+		}
+		return "", from, fmt.Errorf("label %s not found", from)
+	}
+	if fromLabel.GetName() == to {
+		return RenameActionNone, fromLabel.GetName(), nil
+	}
+	if toLabel == nil {
+		return RenameActionRename, fromLabel.GetName(), nil
+	}
+	return RenameActionMerge, fromLabel.GetName(), nil
+}
+
+// PlanRename computes how to rename the label and counts the issues, pull requests and discussions carrying it.
+func PlanRename(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, from, to string) (*RenamePlan, error) {
+	current, err := gh.ListLabels(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+	action, from, err := renameAction(current, from, to)
+	if err != nil {
+		return nil, err
+	}
+	plan := &RenamePlan{
+		Repository: parser.GetRepositoryFullName(repo),
+		From:       from,
+		To:         to,
+		Action:     action,
+		repo:       repo,
+	}
+	if action == RenameActionNone {
+		return plan, nil
+	}
+	qualifier, err := LabelQualifier(from)
+	if err != nil {
+		if action == RenameActionRename {
+			// Renaming in place keeps the label on every item, so the counts are only informational
+			logger.Warn("Items with the label are not counted", "label", from, "error", err)
+			return plan, nil
+		}
+		return nil, err
+	}
+	plan.Issues, err = CountLabelUsage(ctx, g, repo, from)
+	if err != nil {
+		return nil, err
+	}
+	plan.Discussions, err = CountDiscussions(ctx, g, repo, qualifier)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Apply renames or merges the label.
+// A merge relabels every item found by search before deleting the old label. If any item fails, or fewer items than
+// the plan counted are relabeled because the search index lags behind the label changes, the old label is kept
+// so that running the merge again resumes with the remaining items.
+func (p *RenamePlan) Apply(ctx context.Context, g *gh.GitHubClient) error {
+	switch p.Action {
+	case RenameActionNone:
+		return nil
+	case RenameActionRename:
+		_, err := gh.EditLabel(ctx, g, p.repo, p.From, &Label{Name: &p.To})
+		return err
+	}
+
+	qualifier, err := LabelQualifier(p.From)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	issues, err := relabelAll(
+		func() ([]*Issue, error) { return gh.SearchIssues(ctx, g, p.repo, qualifier) },
+		func(issue *Issue) int { return issue.GetNumber() },
+		func(issue *Issue) error {
+			if _, err := gh.AddIssueLabels(ctx, g, p.repo, issue, []string{p.To}); err != nil {
+				return err
+			}
+			if err := gh.RemoveIssueLabel(ctx, g, p.repo, issue, p.From); err != nil {
+				return err
+			}
+			logger.Debug("Relabeled issue", "repository", p.Repository, "number", issue.GetNumber(), "from", p.From, "to", p.To)
+			return nil
+		},
+	)
+	if err == nil {
+		err = checkRelabeled("issues and pull requests", issues, p.Issues)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to relabel issues with label %s: %w", p.From, err))
+	}
+	discussions, err := relabelAll(
+		func() ([]gh.Discussion, error) { return gh.SearchDiscussions(ctx, g, p.repo, qualifier) },
+		func(d gh.Discussion) int { return int(d.Number) },
+		func(d gh.Discussion) error {
+			number := int(d.Number)
+			if _, err := gh.AddDiscussionLabels(ctx, g, p.repo, number, []string{p.To}); err != nil {
+				return fmt.Errorf("failed to add label %s to discussion #%d: %w", p.To, number, err)
+			}
+			if _, err := gh.RemoveDiscussionLabels(ctx, g, p.repo, number, []string{p.From}); err != nil {
+				return fmt.Errorf("failed to remove label %s from discussion #%d: %w", p.From, number, err)
+			}
+			logger.Debug("Relabeled discussion", "repository", p.Repository, "number", number, "from", p.From, "to", p.To)
+			return nil
+		},
+	)
+	if err == nil {
+		err = checkRelabeled("discussions", discussions, p.Discussions)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to relabel discussions with label %s: %w", p.From, err))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("label %s is kept because some items were not relabeled: %w", p.From, errors.Join(errs...))
+	}
+	return gh.DeleteLabel(ctx, g, p.repo, p.From)
+}
+
+// relabelAll relabels the items found by search, and searches again until it finds no item that has not been relabeled yet.
+// A search returns at most 1,000 items, so items beyond that are found by the following searches once the first ones
+// no longer carry the label. Items are relabeled at most once, so that a stale search index cannot make it loop forever.
+// It returns the number of items relabeled successfully.
+func relabelAll[T any](search func() ([]T, error), number func(T) int, relabel func(T) error) (int, error) {
+	var errs []error
+	done := make(map[int]bool)
+	relabeled := 0
+	for {
+		items, err := search()
+		if err != nil {
+			return relabeled, errors.Join(append(errs, err)...)
+		}
+		found := false
+		for _, item := range items {
+			if done[number(item)] {
+				continue
+			}
+			found = true
+			done[number(item)] = true
+			if err := relabel(item); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			relabeled++
+		}
+		if !found {
+			return relabeled, errors.Join(errs...)
+		}
+	}
+}
+
+// checkRelabeled fails if fewer items were relabeled than the plan counted. A stale search index may keep returning
+// the items already relabeled, which ends relabelAll before the remaining items are found.
+func checkRelabeled(kind string, relabeled, planned int) error {
+	if relabeled < planned {
+		return fmt.Errorf("%d of %d %s were not found by search and still carry the label", planned-relabeled, planned, kind)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package labels
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRenameAction(t *testing.T) {
+	current := []*Label{newLabel("bug", "d73a4a", ""), newLabel("Defect", "b60205", ""), newLabel("type: bug", "d73a4a", "")}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		want     RenameAction
+		wantFrom string
+		wantErr  bool
+	}{
+		{name: "rename to new name", from: "bug", to: "kind/bug", want: RenameActionRename, wantFrom: "bug"},
+		{name: "merge into existing label", from: "type: bug", to: "bug", want: RenameActionMerge, wantFrom: "type: bug"},
+		{name: "old name case-insensitive", from: "defect", to: "bug", want: RenameActionMerge, wantFrom: "Defect"},
+		{name: "case-only rename", from: "Defect", to: "defect", want: RenameActionRename, wantFrom: "Defect"},
+		{name: "already completed", from: "kind/bug", to: "bug", want: RenameActionNone, wantFrom: "kind/bug"},
+		{name: "same name", from: "bug", to: "bug", want: RenameActionNone, wantFrom: "bug"},
+		{name: "not found", from: "feature", to: "enhancement", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, from, err := renameAction(current, tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renameAction error: %v", err)
+			}
+			if got != tt.want || from != tt.wantFrom {
+				t.Errorf("renameAction() = (%s, %s), want (%s, %s)", got, from, tt.want, tt.wantFrom)
+			}
+		})
+	}
+}
+
+func TestRelabelAll(t *testing.T) {
+	// 250 items carry the label, and each search returns at most 100 of them
+	carrying := make(map[int]bool)
+	for i := 1; i <= 250; i++ {
+		carrying[i] = true
+	}
+	search := func() ([]int, error) {
+		var items []int
+		for i := 1; i <= 250 && len(items) < 100; i++ {
+			if carrying[i] {
+				items = append(items, i)
+			}
+		}
+		return items, nil
+	}
+	relabeled, err := relabelAll(search, func(i int) int { return i }, func(i int) error {
+		delete(carrying, i)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("relabelAll error: %v", err)
+	}
+	if relabeled != 250 || len(carrying) != 0 {
+		t.Errorf("relabeled %d items, %d still carry the label", relabeled, len(carrying))
+	}
+	if err := checkRelabeled("issues", relabeled, 250); err != nil {
+		t.Errorf("checkRelabeled() = %v, want nil", err)
+	}
+}
+
+func TestRelabelAll_StaleSearch(t *testing.T) {
+	// The search index lags behind, so every search returns the same first page although 250 items carry the label
+	page := make([]int, 100)
+	for i := range page {
+		page[i] = i + 1
+	}
+	relabeled, err := relabelAll(func() ([]int, error) { return page, nil }, func(i int) int { return i }, func(i int) error { return nil })
+	if err != nil {
+		t.Fatalf("relabelAll error: %v", err)
+	}
+	if relabeled != 100 {
+		t.Errorf("relabeled %d items, want 100", relabeled)
+	}
+	err = checkRelabeled("issues", relabeled, 250)
+	if err == nil || !strings.Contains(err.Error(), "150 of 250 issues") {
+		t.Errorf("checkRelabeled() = %v, want an error for the 150 remaining issues", err)
+	}
+}
+
+func TestRelabelAll_Failures(t *testing.T) {
+	// A failed item keeps the label and is returned by every search, but is not retried
+	calls := 0
+	relabeled, err := relabelAll(func() ([]int, error) { return []int{1, 2}, nil }, func(i int) int { return i }, func(i int) error {
+		calls++
+		if i == 2 {
+			return errors.New("forbidden")
+		}
+		return nil
+	})
+	if err == nil || calls != 2 || relabeled != 1 {
+		t.Errorf("relabelAll() = (%d, %v) after %d calls, want (1, error) after 2 calls", relabeled, err, calls)
+	}
+}
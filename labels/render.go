@@ -0,0 +1,392 @@
+package labels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/ddddddO/gtree"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/srz-zumix/go-gh-extension/pkg/render"
+)
+
+// Renderer renders label management results on top of the common renderer.
+type Renderer struct {
+	*render.Renderer
+	exporter cmdutil.Exporter
+}
+
+// NewRenderer creates a new Renderer with the provided exporter
+func NewRenderer(exporter cmdutil.Exporter) *Renderer {
+	return &Renderer{
+		Renderer: render.NewRenderer(exporter),
+		exporter: exporter,
+	}
+}
+
+// tableWriter wraps *tablewriter.Table so that Append errors are logged rather than returned.
+type tableWriter struct {
+	table    *tablewriter.Table
+	renderer *Renderer
+}
+
+func (t *tableWriter) Append(row []string) {
+	if err := t.table.Append(row); err != nil {
+		t.renderer.WriteError(err)
+	}
+}
+
+func (t *tableWriter) Render() error {
+	return t.table.Render()
+}
+
+func (r *Renderer) newTableWriter(header []string) *tableWriter {
+	table := tablewriter.NewTable(r.IO.Out)
+	table.Configure(func(config *tablewriter.Config) {
+		config.Row.Alignment.Global = tw.AlignLeft
+	})
+	anyHeader := make([]any, len(header))
+	for i, h := range header {
+		anyHeader[i] = h
+	}
+	table.Header(anyHeader...)
+	return &tableWriter{table: table, renderer: r}
+}
+
+// colorize renders the color code in its own color when color output is enabled.
+func (r *Renderer) colorize(c string) string {
+	return r.colorizeText(c, c)
+}
+
+// colorizeText renders the text in the color when color output is enabled.
+func (r *Renderer) colorizeText(text, c string) string {
+	if !r.Color || c == "" {
+		return text
+	}
+	red, green, blue, err := render.ToRGB(c)
+	if err != nil {
+		return text
+	}
+	return color.RGB(red, green, blue).Sprint(text)
+}
+
+func (r *Renderer) changeType(t ChangeType) string {
+	if !r.Color {
+		return string(t)
+	}
+	switch t {
+	case ChangeCreate:
+		return color.GreenString(string(t))
+	case ChangeUpdate:
+		return color.YellowString(string(t))
+	case ChangeRename:
+		return color.CyanString(string(t))
+	case ChangeDelete:
+		return color.RedString(string(t))
+	}
+	return string(t)
+}
+
+func transition(before, after string) string {
+	if before == after {
+		return after
+	}
+	return fmt.Sprintf("%s -> %s", before, after)
+}
+
+func (r *Renderer) changeRow(c Change) []string {
+	switch c.Type {
+	case ChangeCreate:
+		return []string{r.changeType(c.Type), c.After.Name, r.colorize(c.After.GetColor()), c.After.GetDescription()}
+	case ChangeDelete:
+		return []string{r.changeType(c.Type), c.Name, r.colorize(c.Before.GetColor()), c.Before.GetDescription()}
+	}
+	colorCell := r.colorize(c.After.GetColor())
+	if c.Before.GetColor() != c.After.GetColor() {
+		colorCell = fmt.Sprintf("%s -> %s", r.colorize(c.Before.GetColor()), r.colorize(c.After.GetColor()))
+	}
+	return []string{
+		r.changeType(c.Type),
+		transition(c.Name, c.After.Name),
+		colorCell,
+		transition(c.Before.GetDescription(), c.After.GetDescription()),
+	}
+}
+
+// Summary returns a one-line summary of the plan.
+func (p *Plan) Summary() string {
+	if !p.HasChanges() {
+		return "no changes"
+	}
+	var parts []string
+	for _, t := range []ChangeType{ChangeCreate, ChangeUpdate, ChangeRename, ChangeDelete} {
+		if n := p.Count(t); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d to %s", n, t))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RenderPlans renders the changes of each plan as a table, or exports the plans if an exporter is set.
+func (r *Renderer) RenderPlans(plans []*Plan) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(plans)
+	}
+	for _, p := range plans {
+		r.WriteLine(fmt.Sprintf("%s: %s", p.Repository, p.Summary()))
+		if !p.HasChanges() {
+			continue
+		}
+		table := r.newTableWriter([]string{"ACTION", "NAME", "COLOR", "DESCRIPTION"})
+		for _, c := range p.Changes {
+			table.Append(r.changeRow(c))
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Renderer) syncStatus(s SyncStatus) string {
+	if !r.Color {
+		return string(s)
+	}
+	switch s {
+	case SyncStatusApplied:
+		return color.GreenString(string(s))
+	case SyncStatusPlanned:
+		return color.YellowString(string(s))
+	case SyncStatusFailed:
+		return color.RedString(string(s))
+	}
+	return string(s)
+}
+
+// RenderSyncResults renders the per-repository report of a bulk sync, or exports the results if an exporter is set.
+func (r *Renderer) RenderSyncResults(results []*SyncResult) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(results)
+	}
+	table := r.newTableWriter([]string{"REPOSITORY", "STATUS", "CHANGES", "ERROR"})
+	for _, result := range results {
+		changes := ""
+		if result.Plan != nil {
+			changes = result.Plan.Summary()
+		}
+		table.Append([]string{result.Repository, r.syncStatus(result.Status), changes, result.Error})
+	}
+	return table.Render()
+}
+
+// RenderRenamePlan renders how the label is renamed and the number of affected items, or exports the plan if an exporter is set.
+func (r *Renderer) RenderRenamePlan(p *RenamePlan) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(p)
+	}
+	table := r.newTableWriter([]string{"ACTION", "NAME", "ISSUES", "DISCUSSIONS"})
+	table.Append([]string{string(p.Action), transition(p.From, p.To), fmt.Sprintf("%d", p.Issues), fmt.Sprintf("%d", p.Discussions)})
+	return table.Render()
+}
+
+func (r *Renderer) itemStatus(s ItemStatus) string {
+	if !r.Color {
+		return string(s)
+	}
+	switch s {
+	case ItemStatusUpdated:
+		return color.GreenString(string(s))
+	case ItemStatusPlanned:
+		return color.YellowString(string(s))
+	case ItemStatusFailed:
+		return color.RedString(string(s))
+	}
+	return string(s)
+}
+
+// RenderItemResults renders the per-target results of a label operation, or exports the results if an exporter is set.
+func (r *Renderer) RenderItemResults(results []*ItemResult) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(results)
+	}
+	table := r.newTableWriter([]string{"TARGET", "STATUS", "LABELS", "ERROR"})
+	for _, result := range results {
+		table.Append([]string{result.Target, r.itemStatus(result.Status), strings.Join(result.Labels, ", "), result.Error})
+	}
+	return table.Render()
+}
+
+// RenderLabelStats renders the usage of each label, or exports the stats if an exporter is set.
+func (r *Renderer) RenderLabelStats(stats []*LabelStats) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(stats)
+	}
+	table := r.newTableWriter([]string{"NAME", "OPEN ISSUES", "CLOSED ISSUES", "OPEN PRS", "CLOSED PRS", "LAST UPDATED ITEM"})
+	for _, s := range stats {
+		lastUpdatedItem := ""
+		if s.LastUpdatedItem != nil {
+			lastUpdatedItem = s.LastUpdatedItem.Format(time.DateOnly)
+		}
+		table.Append([]string{
+			r.colorizeText(s.Name, s.Color),
+			strconv.Itoa(s.OpenIssues),
+			strconv.Itoa(s.ClosedIssues),
+			strconv.Itoa(s.OpenPullRequests),
+			strconv.Itoa(s.ClosedPullRequests),
+			lastUpdatedItem,
+		})
+	}
+	return table.Render()
+}
+
+// RenderFindings renders the findings of repo doctor with the suggested repo rename commands, or exports the findings if an exporter is set.
+func (r *Renderer) RenderFindings(repo string, findings []Finding) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(findings)
+	}
+	if len(findings) == 0 {
+		r.WriteLine(fmt.Sprintf("%s: no problems found", repo))
+		return nil
+	}
+	table := r.newTableWriter([]string{"KIND", "LABEL", "DETAIL", "SUGGESTION"})
+	for _, f := range findings {
+		suggestion := ""
+		if f.Suggestion != nil {
+			suggestion = f.Suggestion.Command(repo)
+		}
+		table.Append([]string{string(f.Kind), f.Label, f.Detail, suggestion})
+	}
+	return table.Render()
+}
+
+// RenderLabelEvents renders the label history of an issue or pull request, or exports the events if an exporter is set.
+func (r *Renderer) RenderLabelEvents(events []LabelEvent) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(events)
+	}
+	table := r.newTableWriter([]string{"TIME", "ACTION", "LABEL", "ACTOR", "AUTOMATION"})
+	for _, e := range events {
+		table.Append([]string{
+			e.Time.Local().Format(time.DateTime),
+			string(e.Action),
+			r.colorizeText(e.Label, e.Color),
+			e.Actor,
+			strconv.FormatBool(e.Automation),
+		})
+	}
+	return table.Render()
+}
+
+// RenderMilestoneSummary renders the per-label counts of a milestone followed by the items per label if they are listed,
+// or exports the summary if an exporter is set.
+func (r *Renderer) RenderMilestoneSummary(summary *MilestoneSummary) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(summary)
+	}
+	table := r.newTableWriter([]string{"GROUP", "NAME", "OPEN ISSUES", "CLOSED ISSUES", "OPEN PRS", "CLOSED PRS", "TOTAL"})
+	hasItems := false
+	for _, s := range summary.Labels {
+		table.Append([]string{
+			s.Group,
+			r.colorizeText(s.Name, s.Color),
+			strconv.Itoa(s.OpenIssues),
+			strconv.Itoa(s.ClosedIssues),
+			strconv.Itoa(s.OpenPullRequests),
+			strconv.Itoa(s.ClosedPullRequests),
+			strconv.Itoa(s.Total),
+		})
+		hasItems = hasItems || len(s.Items) > 0
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+	if !hasItems {
+		return nil
+	}
+	r.WriteLine("")
+	items := r.newTableWriter([]string{"LABEL", "NUMBER", "TYPE", "STATE", "TITLE"})
+	for _, s := range summary.Labels {
+		for _, item := range s.Items {
+			kind := "issue"
+			if item.PullRequest {
+				kind = "pr"
+			}
+			items.Append([]string{r.colorizeText(s.Name, s.Color), fmt.Sprintf("#%d", item.Number), kind, item.State, item.Title})
+		}
+	}
+	return items.Render()
+}
+
+// RenderReleaseNotes renders the release notes as Markdown, or exports the release notes if an exporter is set.
+func (r *Renderer) RenderReleaseNotes(notes *ReleaseNotes) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(notes)
+	}
+	_, err := fmt.Fprint(r.IO.Out, notes.Markdown())
+	return err
+}
+
+// RenderLabelImpacts renders the number of pull requests with each label before and after a change, or exports the impacts if an exporter is set.
+func (r *Renderer) RenderLabelImpacts(impacts []*LabelImpact) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(impacts)
+	}
+	table := r.newTableWriter([]string{"LABEL", "BEFORE", "AFTER", "GAINED", "LOST"})
+	for _, i := range impacts {
+		gained := strconv.Itoa(len(i.Gained))
+		lost := strconv.Itoa(len(i.Lost))
+		if r.Color {
+			if len(i.Gained) > 0 {
+				gained = color.GreenString(gained)
+			}
+			if len(i.Lost) > 0 {
+				lost = color.RedString(lost)
+			}
+		}
+		table.Append([]string{i.Name, strconv.Itoa(i.Before), strconv.Itoa(i.After), gained, lost})
+	}
+	return table.Render()
+}
+
+// RenderCoverageReport renders the unmatched files as a tree followed by the dead globs and overlapping labels,
+// or exports the report if an exporter is set.
+func (r *Renderer) RenderCoverageReport(report *CoverageReport) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(report)
+	}
+	r.WriteLine(fmt.Sprintf("%d of %d files are matched by changed-files globs", report.Matched, report.Files))
+	if len(report.Unmatched) > 0 {
+		r.WriteLine("")
+		r.WriteLine("Unmatched files:")
+		if err := gtree.OutputFromRoot(r.IO.Out, unmatchedTree(report.Unmatched)); err != nil {
+			return err
+		}
+	}
+	if len(report.DeadGlobs) > 0 {
+		r.WriteLine("")
+		r.WriteLine("Globs that match no file:")
+		table := r.newTableWriter([]string{"LABEL", "PATTERN"})
+		for _, d := range report.DeadGlobs {
+			table.Append([]string{d.Label, d.Pattern})
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+	if len(report.Overlaps) > 0 {
+		r.WriteLine("")
+		r.WriteLine("Overlapping labels:")
+		table := r.newTableWriter([]string{"LABELS", "SHARED", "RATIO"})
+		for _, o := range report.Overlaps {
+			table.Append([]string{strings.Join(o.Labels, ", "), strconv.Itoa(o.Shared), fmt.Sprintf("%.0f%%", o.Ratio*100)})
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/go-github/v84/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// maxRateLimitRetries is the number of retries after the rate limit is exceeded.
+const maxRateLimitRetries = 3
+
+// secondaryRateLimitWait is the wait for a secondary rate limit without a Retry-After header.
+const secondaryRateLimitWait = time.Minute
+
+// sleep waits for the duration or until the context is done. Replaced in tests.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitWait returns how long to wait before retrying, or false if the error is not caused by a rate limit.
+func rateLimitWait(err error) (time.Duration, bool) {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		wait := time.Until(rateLimitErr.Rate.Reset.Time) + time.Second
+		return max(wait, time.Second), true
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return secondaryRateLimitWait, true
+	}
+	return 0, false
+}
+
+// retryOnRateLimit calls fn and retries it after waiting when the primary or secondary rate limit is exceeded.
+func retryOnRateLimit(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		wait, ok := rateLimitWait(err)
+		if !ok || attempt >= maxRateLimitRetries {
+			return err
+		}
+		logger.Info("Rate limit exceeded, waiting before retry", "wait", wait.Round(time.Second), "attempt", attempt+1)
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v84/github"
+)
+
+func TestRetryOnRateLimit(t *testing.T) {
+	var waits []time.Duration
+	orig := sleep
+	sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	defer func() { sleep = orig }()
+
+	retryAfter := 5 * time.Second
+	calls := 0
+	err := retryOnRateLimit(context.Background(), func() error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("wrapped: %w", &github.AbuseRateLimitError{RetryAfter: &retryAfter})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retryOnRateLimit error: %v", err)
+	}
+	if calls != 2 || len(waits) != 1 || waits[0] != retryAfter {
+		t.Errorf("calls = %d, waits = %v", calls, waits)
+	}
+
+	waits = nil
+	calls = 0
+	err = retryOnRateLimit(context.Background(), func() error {
+		calls++
+		return &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: time.Now().Add(time.Minute)}}}
+	})
+	if err == nil {
+		t.Error("expected error after retries are exhausted")
+	}
+	if calls != maxRateLimitRetries+1 {
+		t.Errorf("calls = %d, want %d", calls, maxRateLimitRetries+1)
+	}
+
+	calls = 0
+	notRateLimit := errors.New("not found")
+	if err := retryOnRateLimit(context.Background(), func() error { calls++; return notRateLimit }); !errors.Is(err, notRateLimit) || calls != 1 {
+		t.Errorf("non rate limit error should not be retried: calls = %d, err = %v", calls, err)
+	}
+}
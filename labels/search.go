@@ -0,0 +1,94 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v84/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// searchResultLimit is the maximum number of results GitHub returns for a search, however many pages are fetched.
+const searchResultLimit = 1000
+
+// LabelQualifier returns the search qualifier for the label, quoted so that names with spaces are searched as is.
+// GitHub search has no documented way to escape a double quote in a qualifier, so such names are rejected
+// rather than searched in a way that may match nothing.
+func LabelQualifier(name string) (string, error) {
+	if strings.Contains(name, `"`) {
+		return "", fmt.Errorf("label %s cannot be searched because its name contains a double quote", name)
+	}
+	return `label:"` + name + `"`, nil
+}
+
+// LabelQuery returns the search query for issues and pull requests with the label in the repository.
+func LabelQuery(repo repository.Repository, name string) (string, error) {
+	qualifier, err := LabelQualifier(name)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`repo:%s %s`, parser.GetRepositoryFullName(repo), qualifier), nil
+}
+
+// CountIssues returns the number of issues and pull requests matching the search query without fetching them all.
+func CountIssues(ctx context.Context, g *gh.GitHubClient, query string) (int, error) {
+	total, _, err := searchLatest(ctx, g, query)
+	return total, err
+}
+
+// searchLatest returns the number of issues and pull requests matching the search query and the most recently updated one.
+// The search is retried when the rate limit is exceeded.
+func searchLatest(ctx context.Context, g *gh.GitHubClient, query string) (int, *Issue, error) {
+	opts := &github.SearchOptions{Sort: "updated", Order: "desc", ListOptions: github.ListOptions{PerPage: 1}}
+	var result *github.IssuesSearchResult
+	err := retryOnRateLimit(ctx, func() error {
+		var err error
+		result, _, err = g.GetClient().Search.Issues(ctx, query, opts)
+		return err
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to search issues with query '%s': %w", query, err)
+	}
+	logger.Debug("Counted issues", "query", query, "total", result.GetTotal())
+	var latest *Issue
+	if len(result.Issues) > 0 {
+		latest = result.Issues[0]
+	}
+	return result.GetTotal(), latest, nil
+}
+
+// CountLabelUsage returns the number of issues and pull requests with the label in the repository.
+func CountLabelUsage(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, name string) (int, error) {
+	query, err := LabelQuery(repo, name)
+	if err != nil {
+		return 0, err
+	}
+	return CountIssues(ctx, g, query)
+}
+
+// CountDiscussions returns the number of discussions of the repository matching the search query without fetching them all.
+func CountDiscussions(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, query string) (int, error) {
+	client, err := g.GetOrCreateGraphQLClient()
+	if err != nil {
+		return 0, err
+	}
+	var q struct {
+		Search struct {
+			DiscussionCount int
+		} `graphql:"search(query: $query, type: DISCUSSION, first: 1)"`
+	}
+	query = fmt.Sprintf("repo:%s %s", parser.GetRepositoryFullName(repo), query)
+	err = retryOnRateLimit(ctx, func() error {
+		return client.Query(ctx, &q, map[string]any{"query": githubv4.String(query)})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to search discussions with query '%s': %w", query, err)
+	}
+	logger.Debug("Counted discussions", "query", query, "total", q.Search.DiscussionCount)
+	return q.Search.DiscussionCount, nil
+}
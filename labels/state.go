@@ -0,0 +1,68 @@
+package labels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// SyncState records the destination repositories that have been synced, so that an interrupted run can be resumed.
+type SyncState struct {
+	Completed []string `json:"completed"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// LoadSyncState loads the state file. A missing file results in an empty state.
+func LoadSyncState(path string) (*SyncState, error) {
+	state := &SyncState{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// IsCompleted returns whether the repository has already been synced.
+func (s *SyncState) IsCompleted(repo string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Contains(s.Completed, repo)
+}
+
+// MarkCompleted records the repository as synced and saves the state file.
+func (s *SyncState) MarkCompleted(repo string) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.Contains(s.Completed, repo) {
+		s.Completed = append(s.Completed, repo)
+	}
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,41 @@
+package labels
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncState_Resume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	state, err := LoadSyncState(path)
+	if err != nil {
+		t.Fatalf("LoadSyncState error: %v", err)
+	}
+	if state.IsCompleted("org/api") {
+		t.Error("empty state should not have completed repositories")
+	}
+	if err := state.MarkCompleted("org/api"); err != nil {
+		t.Fatalf("MarkCompleted error: %v", err)
+	}
+
+	resumed, err := LoadSyncState(path)
+	if err != nil {
+		t.Fatalf("LoadSyncState error: %v", err)
+	}
+	if !resumed.IsCompleted("org/api") {
+		t.Error("org/api should be completed after resume")
+	}
+	if resumed.IsCompleted("org/web") {
+		t.Error("org/web should not be completed")
+	}
+}
+
+func TestSyncState_Nil(t *testing.T) {
+	var state *SyncState
+	if state.IsCompleted("org/api") {
+		t.Error("nil state should not have completed repositories")
+	}
+	if err := state.MarkCompleted("org/api"); err != nil {
+		t.Errorf("MarkCompleted on nil state should be a no-op: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package labels
+
+import (
+	"context"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+// LabelStats is the usage of a label in a repository.
+type LabelStats struct {
+	Name               string     `json:"name"`
+	Color              string     `json:"color"`
+	Description        string     `json:"description"`
+	OpenIssues         int        `json:"openIssues"`
+	ClosedIssues       int        `json:"closedIssues"`
+	OpenPullRequests   int        `json:"openPullRequests"`
+	ClosedPullRequests int        `json:"closedPullRequests"`
+	Total              int        `json:"total"`
+	LastUpdatedItem    *time.Time `json:"lastUpdatedItem,omitempty"`
+}
+
+// IsUnused returns whether no issue or pull request has the label.
+func (s *LabelStats) IsUnused() bool {
+	return s.Total == 0
+}
+
+// newLabelStats derives the per-state counts from the totals of all items, issues only, open issues and open pull requests.
+func newLabelStats(label *Label, total, issues, openIssues, openPullRequests int, lastUpdatedItem *time.Time) *LabelStats {
+	return &LabelStats{
+		Name:               label.GetName(),
+		Color:              NormalizeColor(label.GetColor()),
+		Description:        label.GetDescription(),
+		OpenIssues:         openIssues,
+		ClosedIssues:       max(issues-openIssues, 0),
+		OpenPullRequests:   openPullRequests,
+		ClosedPullRequests: max(total-issues-openPullRequests, 0),
+		Total:              total,
+		LastUpdatedItem:    lastUpdatedItem,
+	}
+}
+
+// ComputeLabelStats counts the issues and pull requests with the label by state.
+// LastUpdatedItem is when the most recently updated item with the label was updated, which is not necessarily when
+// the label was applied, since any comment or edit updates an item.
+func ComputeLabelStats(ctx context.Context, g *gh.GitHubClient, repo repository.Repository, label *Label) (*LabelStats, error) {
+	query, err := LabelQuery(repo, label.GetName())
+	if err != nil {
+		return nil, err
+	}
+	total, latest, err := searchLatest(ctx, g, query)
+	if err != nil {
+		return nil, err
+	}
+	var lastUpdatedItem *time.Time
+	if latest != nil && latest.UpdatedAt != nil {
+		lastUpdatedItem = &latest.UpdatedAt.Time
+	}
+	if total == 0 {
+		return newLabelStats(label, 0, 0, 0, 0, nil), nil
+	}
+	issues, err := CountIssues(ctx, g, query+" is:issue")
+	if err != nil {
+		return nil, err
+	}
+	openIssues, err := CountIssues(ctx, g, query+" is:issue is:open")
+	if err != nil {
+		return nil, err
+	}
+	openPullRequests, err := CountIssues(ctx, g, query+" is:pr is:open")
+	if err != nil {
+		return nil, err
+	}
+	return newLabelStats(label, total, issues, openIssues, openPullRequests, lastUpdatedItem), nil
+}
+
+// ComputeStats computes the usage of every label of the repository, sorted by name.
+func ComputeStats(ctx context.Context, g *gh.GitHubClient, repo repository.Repository) ([]*LabelStats, error) {
+	repoLabels, err := gh.ListLabels(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+	definitions := NewDefinitions(repoLabels)
+	byName := make(map[string]*Label, len(repoLabels))
+	for _, l := range repoLabels {
+		byName[l.GetName()] = l
+	}
+	stats := make([]*LabelStats, 0, len(definitions))
+	for _, d := range definitions {
+		s, err := ComputeLabelStats(ctx, g, repo, byName[d.Name])
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
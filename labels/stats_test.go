@@ -0,0 +1,21 @@
+package labels
+
+import "testing"
+
+func TestNewLabelStats(t *testing.T) {
+	s := newLabelStats(newLabel("bug", "#D73A4A", "Something isn't working"), 10, 6, 4, 1, nil)
+	if s.OpenIssues != 4 || s.ClosedIssues != 2 || s.OpenPullRequests != 1 || s.ClosedPullRequests != 3 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.Color != "d73a4a" {
+		t.Errorf("color should be normalized: %s", s.Color)
+	}
+	if s.IsUnused() {
+		t.Error("bug should be used")
+	}
+
+	unused := newLabelStats(newLabel("wontfix", "ffffff", ""), 0, 0, 0, 0, nil)
+	if !unused.IsUnused() {
+		t.Error("wontfix should be unused")
+	}
+}
@@ -0,0 +1,66 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// SyncOptions controls how the labels of a source repository are reflected to a destination.
+type SyncOptions struct {
+	// Update updates destination labels that differ from the source.
+	Update bool
+	// Prune deletes destination labels that do not exist in the source.
+	Prune bool
+	// KeepUsed keeps pruned labels that are still used by issues or pull requests.
+	KeepUsed bool
+}
+
+// CopyOptions returns the options of repo copy: missing labels are created and existing labels are updated only if force is set.
+func CopyOptions(force bool) SyncOptions {
+	return SyncOptions{Update: force}
+}
+
+// MirrorOptions returns the options of repo sync: labels are created and updated, and labels not in the source are deleted.
+// Labels still in use are kept unless force is set.
+func MirrorOptions(force bool) SyncOptions {
+	return SyncOptions{Update: true, Prune: true, KeepUsed: !force}
+}
+
+// ComputeSyncPlan computes the changes to reflect the source labels to the destination repository.
+func ComputeSyncPlan(ctx context.Context, g *gh.GitHubClient, dst repository.Repository, source []*Label, opts SyncOptions) (*Plan, error) {
+	plan, err := ComputePlan(ctx, g, dst, NewDefinitions(source), DiffOptions{Update: opts.Update, Prune: opts.Prune})
+	if err != nil {
+		return nil, err
+	}
+	if opts.Prune && opts.KeepUsed {
+		if err := plan.excludeUsedDeletes(ctx, g); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// excludeUsedDeletes drops delete changes of labels that are still used by issues or pull requests.
+func (p *Plan) excludeUsedDeletes(ctx context.Context, g *gh.GitHubClient) error {
+	changes := make([]Change, 0, len(p.Changes))
+	for _, c := range p.Changes {
+		if c.Type == ChangeDelete {
+			count, err := CountLabelUsage(ctx, g, p.repo, c.Name)
+			if err != nil {
+				return fmt.Errorf("failed to count usage of label %s: %w", c.Name, err)
+			}
+			if count > 0 {
+				logger.Debug("Keeping label in use", "repository", parser.GetRepositoryFullName(p.repo), "name", c.Name, "usage", count)
+				continue
+			}
+		}
+		changes = append(changes, c)
+	}
+	p.Changes = changes
+	return nil
+}
@@ -0,0 +1,112 @@
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// TargetFilter selects the repositories of an owner to reflect labels to.
+type TargetFilter struct {
+	// Include selects repositories whose name matches any of the patterns. All repositories are selected if empty.
+	Include []*regexp.Regexp
+	// Exclude drops repositories whose name matches any of the patterns.
+	Exclude []*regexp.Regexp
+	// Topics selects repositories that have any of the topics. All repositories are selected if empty.
+	Topics []string
+	// SkipArchived drops archived repositories.
+	SkipArchived bool
+	// SkipForks drops forked repositories.
+	SkipForks bool
+}
+
+// NewTargetFilter creates a TargetFilter, compiling the include and exclude patterns.
+func NewTargetFilter(include, exclude, topics []string, skipArchived, skipForks bool) (*TargetFilter, error) {
+	includePatterns, err := compilePatterns(include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	excludePatterns, err := compilePatterns(exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	return &TargetFilter{
+		Include:      includePatterns,
+		Exclude:      excludePatterns,
+		Topics:       topics,
+		SkipArchived: skipArchived,
+		SkipForks:    skipForks,
+	}, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func matchAnyPattern(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns whether the repository is selected by the filter.
+func (f *TargetFilter) Match(repo *Repository) bool {
+	if repo == nil || repo.Name == nil {
+		return false
+	}
+	if f.SkipArchived && repo.GetArchived() {
+		return false
+	}
+	if f.SkipForks && repo.GetFork() {
+		return false
+	}
+	name := repo.GetName()
+	if len(f.Include) > 0 && !matchAnyPattern(f.Include, name) {
+		return false
+	}
+	if matchAnyPattern(f.Exclude, name) {
+		return false
+	}
+	if len(f.Topics) > 0 {
+		found := false
+		for _, topic := range f.Topics {
+			if slices.Contains(repo.Topics, strings.ToLower(topic)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Select returns the full names of the selected repositories sorted by name, excluding the given repositories (e.g. the source).
+func (f *TargetFilter) Select(repos []*Repository, excludes ...string) []string {
+	var selected []string
+	for _, r := range repos {
+		if !f.Match(r) {
+			continue
+		}
+		fullName := r.GetFullName()
+		if slices.ContainsFunc(excludes, func(e string) bool { return strings.EqualFold(e, fullName) }) {
+			continue
+		}
+		selected = append(selected, fullName)
+	}
+	slices.Sort(selected)
+	return selected
+}
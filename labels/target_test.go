@@ -0,0 +1,80 @@
+package labels
+
+import (
+	"slices"
+	"testing"
+)
+
+func newRepository(name string, topics []string, archived, fork bool) *Repository {
+	return &Repository{
+		Name:     Ptr(name),
+		FullName: Ptr("org/" + name),
+		Topics:   topics,
+		Archived: Ptr(archived),
+		Fork:     Ptr(fork),
+	}
+}
+
+func TestTargetFilter_Select(t *testing.T) {
+	repos := []*Repository{
+		newRepository("api", []string{"backend"}, false, false),
+		newRepository("web", []string{"frontend"}, false, false),
+		newRepository("web-legacy", []string{"frontend"}, true, false),
+		newRepository("api-fork", nil, false, true),
+		newRepository(".github", nil, false, false),
+	}
+
+	tests := []struct {
+		name         string
+		include      []string
+		exclude      []string
+		topics       []string
+		skipArchived bool
+		skipForks    bool
+		want         []string
+	}{
+		{
+			name: "all except source",
+			want: []string{"org/api", "org/api-fork", "org/web", "org/web-legacy"},
+		},
+		{
+			name:    "include and exclude",
+			include: []string{"^api", "^web"},
+			exclude: []string{"-fork$"},
+			want:    []string{"org/api", "org/web", "org/web-legacy"},
+		},
+		{
+			name:   "topic",
+			topics: []string{"Frontend"},
+			want:   []string{"org/web", "org/web-legacy"},
+		},
+		{
+			name:         "skip archived and forks",
+			skipArchived: true,
+			skipForks:    true,
+			want:         []string{"org/api", "org/web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewTargetFilter(tt.include, tt.exclude, tt.topics, tt.skipArchived, tt.skipForks)
+			if err != nil {
+				t.Fatalf("NewTargetFilter error: %v", err)
+			}
+			got := filter.Select(repos, "ORG/.github")
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTargetFilter_InvalidPattern(t *testing.T) {
+	if _, err := NewTargetFilter([]string{"("}, nil, nil, false, false); err == nil {
+		t.Error("expected error for invalid include pattern")
+	}
+	if _, err := NewTargetFilter(nil, []string{"("}, nil, false, false); err == nil {
+		t.Error("expected error for invalid exclude pattern")
+	}
+}
@@ -0,0 +1,12 @@
+package labels
+
+import "github.com/google/go-github/v84/github"
+
+type Label = github.Label
+type Issue = github.Issue
+type Repository = github.Repository
+type PullRequest = github.PullRequest
+
+func Ptr[T any](v T) *T {
+	return &v
+}